@@ -20,6 +20,7 @@ import (
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
 	"github.com/termora/berry/helper"
+	"github.com/termora/berry/webhooks"
 )
 
 // Bot is the main bot struct
@@ -38,6 +39,11 @@ type Bot struct {
 
 	Helper *helper.Helper
 
+	Webhooks *webhooks.Dispatcher
+
+	userCommands   map[string]*userCommand
+	userCommandsMu sync.RWMutex
+
 	redis radix.Client
 }
 
@@ -53,6 +59,7 @@ func New(
 		Sentry:    hub,
 		UseSentry: hub != nil,
 		Guilds:    map[discord.GuildID]discord.Guild{},
+		Webhooks:  webhooks.New(db),
 	}
 
 	if config.Core.Redis != "" {
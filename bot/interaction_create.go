@@ -62,6 +62,22 @@ func (bot *Bot) InteractionCreate(ic *gateway.InteractionCreateEvent) {
 		}
 	}()
 
+	if cmd, target, ok := bot.userCommand(ic); ok {
+		ctx, err := bot.Router.NewSlashContext(ic)
+		if err != nil {
+			log.Errorf("Couldn't create slash context: %v", err)
+			return
+		}
+
+		err = cmd.Command(ctx, target)
+		if err != nil {
+			log.Errorf("Couldn't execute user command %q: %v", cmd.Name, err)
+		}
+
+		bot.Stats.IncCommand()
+		return
+	}
+
 	ctx, err := bot.Router.NewSlashContext(ic)
 	if err != nil {
 		log.Errorf("Couldn't create slash context: %v", err)
@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/termora/berry/db"
+)
+
+// IsDirector returns true if the member is a bot owner, or has either the admin or director role.
+func (bot *Bot) IsDirector(m *discord.Member) bool {
+	if m == nil {
+		return false
+	}
+	return db.StaffScope(bot.Config.Bot, m.User.ID, m.RoleIDs).Has(db.ScopeDirector)
+}
+
+// IsAdmin returns true if the member is a bot owner, or has the admin role.
+func (bot *Bot) IsAdmin(m *discord.Member) bool {
+	if m == nil {
+		return false
+	}
+	return db.StaffScope(bot.Config.Bot, m.User.ID, m.RoleIDs).Has(db.ScopeAdmin)
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/bcr"
+)
+
+// userCommand is a command shown in the context menu of users.
+// bcr only handles chat input commands, so these are registered and synced separately.
+type userCommand struct {
+	Name    string
+	Command func(ctx *bcr.SlashContext, target discord.User) error
+}
+
+// AddUserCommand adds a user context menu command.
+// The name is shown in the context menu, and can have spaces and capital letters.
+func (bot *Bot) AddUserCommand(name string, cmd func(ctx *bcr.SlashContext, target discord.User) error) {
+	bot.userCommandsMu.Lock()
+	defer bot.userCommandsMu.Unlock()
+
+	if bot.userCommands == nil {
+		bot.userCommands = map[string]*userCommand{}
+	}
+	bot.userCommands[name] = &userCommand{Name: name, Command: cmd}
+}
+
+// userCommand returns the user command for the interaction, if it's a user command
+func (bot *Bot) userCommand(ic *gateway.InteractionCreateEvent) (cmd *userCommand, target discord.User, ok bool) {
+	data, ok := ic.Data.(*discord.CommandInteraction)
+	if !ok {
+		return nil, target, false
+	}
+
+	bot.userCommandsMu.RLock()
+	cmd, ok = bot.userCommands[data.Name]
+	bot.userCommandsMu.RUnlock()
+	if !ok {
+		return nil, target, false
+	}
+
+	// user commands resolve exactly one user, the target
+	for _, u := range data.Resolved.Users {
+		return cmd, u, true
+	}
+	return nil, target, false
+}
+
+// SyncUserCommands creates all user commands.
+// This has to be called *after* syncing slash commands, as that overwrites all existing commands.
+func (bot *Bot) SyncUserCommands(guildIDs ...discord.GuildID) (err error) {
+	appID := discord.AppID(bot.Router.Bot.ID)
+	s, _ := bot.Router.StateFromGuildID(0)
+
+	bot.userCommandsMu.RLock()
+	defer bot.userCommandsMu.RUnlock()
+
+	for _, cmd := range bot.userCommands {
+		data := api.CreateCommandData{
+			Type: discord.UserCommand,
+			Name: cmd.Name,
+		}
+
+		if len(guildIDs) == 0 {
+			_, err = s.CreateCommand(appID, data)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, id := range guildIDs {
+			_, err = s.CreateGuildCommand(appID, id, data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
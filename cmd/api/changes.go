@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 500
+)
+
+// change is a change log entry along with the object's current state.
+// Data is nil for deletions.
+type change struct {
+	db.Change
+	Data interface{} `json:"data"`
+}
+
+type changesResponse struct {
+	Changes []change `json:"changes"`
+	// Cursor should be passed as `since` to get the next page
+	Cursor string `json:"cursor"`
+	More   bool   `json:"more"`
+}
+
+func (s *Server) changes(w http.ResponseWriter, r *http.Request) {
+	var (
+		since int64
+		limit = defaultChangesLimit
+		err   error
+	)
+
+	if v := r.FormValue("since"); v != "" {
+		since, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || since < 0 {
+			writeStatus(w, r, http.StatusBadRequest, "since: invalid cursor")
+			return
+		}
+	}
+	if v := r.FormValue("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxChangesLimit {
+			writeStatus(w, r, http.StatusBadRequest, "limit: must be between 1 and 500")
+			return
+		}
+	}
+
+	changes, err := s.db.Changes(since, limit)
+	if err != nil {
+		if err == db.ErrInvalidCursor {
+			writeStatus(w, r, http.StatusBadRequest, "since: invalid cursor")
+			return
+		}
+		log.Errorf("Error getting changes since %v: %v", since, err)
+		writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	resp := changesResponse{
+		Changes: []change{},
+		Cursor:  strconv.FormatInt(since, 10),
+		More:    len(changes) == limit,
+	}
+	if len(changes) == 0 {
+		render.JSON(w, r, resp)
+		return
+	}
+	resp.Cursor = strconv.FormatInt(changes[len(changes)-1].ID, 10)
+
+	// only the latest change to each object in this page is returned,
+	// as the object's current state includes all earlier changes
+	type key struct {
+		subject string
+		id      int
+	}
+	latest := map[key]int{}
+	for i, c := range changes {
+		latest[key{c.Subject, c.SubjectID}] = i
+	}
+
+	ids := map[string][]int{}
+	for i, c := range changes {
+		if latest[key{c.Subject, c.SubjectID}] != i || c.Action == "delete" {
+			continue
+		}
+		ids[c.Subject] = append(ids[c.Subject], c.SubjectID)
+	}
+
+	data, err := s.changeData(ids)
+	if err != nil {
+		log.Errorf("Error getting changed objects: %v", err)
+		writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	for i, c := range changes {
+		k := key{c.Subject, c.SubjectID}
+		if latest[k] != i {
+			continue
+		}
+
+		if c.Action == "delete" {
+			resp.Changes = append(resp.Changes, change{Change: c})
+			continue
+		}
+
+		// if the object is gone it was deleted after this page, so its tombstone will be in a later one
+		v, ok := data[k.subject][k.id]
+		if !ok {
+			continue
+		}
+		resp.Changes = append(resp.Changes, change{Change: c, Data: v})
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// changeData gets the current state of the given objects, by subject and ID
+func (s *Server) changeData(ids map[string][]int) (map[string]map[int]interface{}, error) {
+	data := map[string]map[int]interface{}{
+		"term":        {},
+		"explanation": {},
+		"pronouns":    {},
+	}
+
+	if len(ids["term"]) > 0 {
+		terms, err := s.db.TermsByID(ids["term"])
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range terms {
+			data["term"][t.ID] = t
+		}
+	}
+
+	if len(ids["explanation"]) > 0 {
+		explanations, err := s.db.ExplanationsByID(ids["explanation"])
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range explanations {
+			data["explanation"][e.ID] = e
+		}
+	}
+
+	if len(ids["pronouns"]) > 0 {
+		pronouns, err := s.db.PronounSetsByID(ids["pronouns"])
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pronouns {
+			data["pronouns"][p.ID] = p
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,15 @@
+package api
+
+import "net/http"
+
+// cors allows reading the API from scripts on other sites, such as the site's embed.js.
+// Only GET and HEAD requests are allowed. Preflight requests aren't answered, so scripts can't send API keys.
+func cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Expose-Headers", "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
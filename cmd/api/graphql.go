@@ -0,0 +1,534 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/render"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/jackc/pgx/v4"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+)
+
+// Limits for GraphQL queries
+const (
+	graphqlMaxDepth       = 8
+	graphqlMaxQueryLength = 10000
+	// graphqlMaxComplexity is the total cost a single query can use, see the field costs below
+	graphqlMaxComplexity = 1000
+	graphqlMaxLimit      = 500
+
+	// a GraphQL query counts as this many requests for rate limiting
+	graphqlRequestCost = 5
+)
+
+// Costs of resolving GraphQL fields
+const (
+	// every term returned
+	termCost = 1
+	// every category, tag, explanation, or pronoun set returned by a list field
+	itemCost = 1
+	// every field that needs its own database query
+	queryCost = 10
+	// a search
+	searchCost = 25
+)
+
+var errComplexity = errors.New("query is too complex")
+
+type complexityKey struct{}
+
+type complexityBudget struct {
+	mu   sync.Mutex
+	left int
+}
+
+// charge takes n from the query's complexity budget, returning an error if it runs out
+func charge(ctx context.Context, n int) error {
+	b, ok := ctx.Value(complexityKey{}).(*complexityBudget)
+	if !ok {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.left < n {
+		b.left = 0
+		return errComplexity
+	}
+	b.left -= n
+	return nil
+}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (s *Server) newGraphQLSchema() *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &gqlQuery{s: s},
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(10),
+	)
+}
+
+func (s *Server) graphql(w http.ResponseWriter, r *http.Request) {
+	var req graphqlRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.Query) > graphqlMaxQueryLength {
+		writeError(w, r, http.StatusBadRequest, "query is too long")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), complexityKey{}, &complexityBudget{left: graphqlMaxComplexity})
+
+	render.JSON(w, r, s.gqlSchema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+type gqlQuery struct {
+	s *Server
+}
+
+func (q *gqlQuery) Term(ctx context.Context, args struct{ ID int32 }) (*gqlTerm, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	t, err := q.s.db.GetTerm(int(args.ID))
+	if err != nil {
+		if pkgerrors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gqlTerm{q.s, t}, nil
+}
+
+type termsArgs struct {
+	Category *int32
+	Tags     *[]string
+	Flags    int32
+	Limit    int32
+	Offset   int32
+}
+
+func (q *gqlQuery) Terms(ctx context.Context, args termsArgs) (*gqlTermPage, error) {
+	f := db.TermFilter{Mask: search.TermFlag(args.Flags)}
+	if args.Category != nil {
+		f.Category = int(*args.Category)
+	}
+	if args.Tags != nil {
+		for _, t := range *args.Tags {
+			f.Tags = append(f.Tags, strings.ToLower(strings.TrimSpace(t)))
+		}
+	}
+	return q.s.termPage(ctx, f, args.Limit, args.Offset)
+}
+
+func (s *Server) termPage(ctx context.Context, f db.TermFilter, limit, offset int32) (*gqlTermPage, error) {
+	if limit < 1 || limit > graphqlMaxLimit {
+		return nil, errors.New("limit must be between 1 and 500")
+	}
+	if offset < 0 {
+		return nil, errors.New("offset can't be negative")
+	}
+	f.Limit, f.Offset = int(limit), int(offset)
+
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	terms, total, err := s.db.FilterTerms(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(terms)*termCost); err != nil {
+		return nil, err
+	}
+
+	return &gqlTermPage{terms: s.gqlTerms(terms), total: int32(total)}, nil
+}
+
+func (q *gqlQuery) Search(ctx context.Context, args struct {
+	Input    string
+	Category *int32
+	Limit    int32
+	Ignore   *[]string
+}) ([]*gqlTerm, error) {
+	if args.Limit < 0 || args.Limit > graphqlMaxLimit {
+		return nil, errors.New("limit must be between 0 and 500")
+	}
+	if err := charge(ctx, searchCost); err != nil {
+		return nil, err
+	}
+
+	ignore := []string{}
+	if args.Ignore != nil {
+		ignore = *args.Ignore
+	}
+
+	var (
+		terms []*db.Term
+		err   error
+	)
+	if args.Category != nil {
+		terms, err = q.s.db.SearchCat(args.Input, int(*args.Category), int(args.Limit), ignore)
+	} else {
+		terms, err = q.s.db.Search(args.Input, int(args.Limit), ignore)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(terms)*termCost); err != nil {
+		return nil, err
+	}
+	return q.s.gqlTerms(terms), nil
+}
+
+func (q *gqlQuery) Category(ctx context.Context, args struct{ ID int32 }) (*gqlCategory, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	c := q.s.db.CategoryFromID(int(args.ID))
+	if c.ID == 0 {
+		return nil, nil
+	}
+	return &gqlCategory{q.s, c.ID, c.Name}, nil
+}
+
+func (q *gqlQuery) Categories(ctx context.Context) ([]*gqlCategory, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	cats, err := q.s.db.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(cats)*itemCost); err != nil {
+		return nil, err
+	}
+
+	out := make([]*gqlCategory, 0, len(cats))
+	for _, c := range cats {
+		out = append(out, &gqlCategory{q.s, c.ID, c.Name})
+	}
+	return out, nil
+}
+
+func (q *gqlQuery) Tags(ctx context.Context) ([]*gqlTag, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	tags, err := q.s.db.Tags()
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(tags)*itemCost); err != nil {
+		return nil, err
+	}
+
+	out := make([]*gqlTag, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, &gqlTag{q.s, t})
+	}
+	return out, nil
+}
+
+func (q *gqlQuery) Explanation(ctx context.Context, args struct{ ID int32 }) (*gqlExplanation, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	e, err := q.s.db.ExplanationByID(int(args.ID))
+	if err != nil {
+		if pkgerrors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gqlExplanation{e}, nil
+}
+
+func (q *gqlQuery) Explanations(ctx context.Context) ([]*gqlExplanation, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	e, err := q.s.db.GetAllExplanations()
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(e)*itemCost); err != nil {
+		return nil, err
+	}
+	return gqlExplanations(e), nil
+}
+
+func (q *gqlQuery) PronounSet(ctx context.Context, args struct{ ID int32 }) (*gqlPronounSet, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	p, err := q.s.db.PronounSetByID(int(args.ID))
+	if err != nil {
+		if pkgerrors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gqlPronounSet{p}, nil
+}
+
+func (q *gqlQuery) Pronouns(ctx context.Context, args struct{ Language *string }) ([]*gqlPronounSet, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	lang := ""
+	if args.Language != nil {
+		lang = *args.Language
+	}
+	if _, ok := db.PronounLanguageByCode(lang); !ok {
+		return nil, errors.New("unknown language")
+	}
+
+	p, err := q.s.db.LanguagePronouns(lang, db.AlphabeticPronounOrder)
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, len(p)*itemCost); err != nil {
+		return nil, err
+	}
+	return gqlPronounSets(p), nil
+}
+
+func (q *gqlQuery) Contributors(ctx context.Context) ([]*gqlContributorCategory, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	cats, err := q.s.db.ContributorCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*gqlContributorCategory, 0, len(cats))
+	for _, c := range cats {
+		if err := charge(ctx, queryCost); err != nil {
+			return nil, err
+		}
+
+		contributors, err := q.s.db.Contributors(c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		cat := &gqlContributorCategory{name: c.Name, contributors: []*gqlContributor{}}
+		for _, u := range contributors {
+			name := u.Name
+			if u.Override != nil {
+				name = *u.Override
+			}
+			cat.contributors = append(cat.contributors, &gqlContributor{name})
+		}
+		out = append(out, cat)
+	}
+	return out, nil
+}
+
+type gqlTermPage struct {
+	terms []*gqlTerm
+	total int32
+}
+
+func (p *gqlTermPage) Terms() []*gqlTerm { return p.terms }
+func (p *gqlTermPage) Total() int32      { return p.total }
+
+type gqlTerm struct {
+	s *Server
+	t *db.Term
+}
+
+func (s *Server) gqlTerms(terms []*db.Term) []*gqlTerm {
+	out := make([]*gqlTerm, 0, len(terms))
+	for _, t := range terms {
+		out = append(out, &gqlTerm{s, t})
+	}
+	return out
+}
+
+func (t *gqlTerm) ID() int32                  { return int32(t.t.ID) }
+func (t *gqlTerm) Name() string               { return t.t.Name }
+func (t *gqlTerm) Aliases() []string          { return nonNil(t.t.Aliases) }
+func (t *gqlTerm) Description() string        { return t.t.Description }
+func (t *gqlTerm) Note() string               { return t.t.Note }
+func (t *gqlTerm) Source() string             { return t.t.Source }
+func (t *gqlTerm) Created() graphql.Time      { return graphql.Time{Time: t.t.Created} }
+func (t *gqlTerm) LastModified() graphql.Time { return graphql.Time{Time: t.t.LastModified} }
+func (t *gqlTerm) ContentWarnings() string    { return t.t.ContentWarnings }
+func (t *gqlTerm) ImageURL() string           { return t.t.ImageURL }
+func (t *gqlTerm) Flags() int32               { return int32(t.t.Flags) }
+
+func (t *gqlTerm) Category() *gqlCategory {
+	return &gqlCategory{t.s, t.t.Category, t.t.CategoryName}
+}
+
+func (t *gqlTerm) Tags() []*gqlTag {
+	out := make([]*gqlTag, 0, len(t.t.DisplayTags))
+	for _, tag := range t.t.DisplayTags {
+		out = append(out, &gqlTag{t.s, tag})
+	}
+	return out
+}
+
+func (t *gqlTerm) Explanations(ctx context.Context) ([]*gqlExplanation, error) {
+	if err := charge(ctx, queryCost); err != nil {
+		return nil, err
+	}
+
+	e, err := t.s.db.ExplanationsMentioning(append([]string{t.t.Name}, t.t.Aliases...))
+	if err != nil {
+		return nil, err
+	}
+	return gqlExplanations(e), nil
+}
+
+// pronounMentionRegexp matches pronouns like "xe/xem" or "ae/aer/aers"
+var pronounMentionRegexp = regexp.MustCompile(`[\p{L}']+(?:/[\p{L}']+)+`)
+
+// maxPronounMentions is the maximum number of pronoun sets looked up for a single term
+const maxPronounMentions = 5
+
+func (t *gqlTerm) Pronouns(ctx context.Context) ([]*gqlPronounSet, error) {
+	text := strings.Join(append([]string{t.t.Name, t.t.Description}, t.t.Aliases...), "\n")
+
+	var (
+		out  = []*gqlPronounSet{}
+		seen = map[int]bool{}
+	)
+	for _, m := range pronounMentionRegexp.FindAllString(text, maxPronounMentions) {
+		if err := charge(ctx, queryCost); err != nil {
+			return nil, err
+		}
+
+		sets, err := t.s.db.GetPronoun(strings.Split(m, "/")...)
+		if err != nil {
+			if pkgerrors.Cause(err) == pgx.ErrNoRows || err == db.ErrTooManyForms {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, p := range sets {
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				out = append(out, &gqlPronounSet{p})
+			}
+		}
+	}
+	return out, nil
+}
+
+type gqlCategory struct {
+	s    *Server
+	id   int
+	name string
+}
+
+type pageArgs struct {
+	Flags  int32
+	Limit  int32
+	Offset int32
+}
+
+func (c *gqlCategory) ID() int32    { return int32(c.id) }
+func (c *gqlCategory) Name() string { return c.name }
+
+func (c *gqlCategory) Terms(ctx context.Context, args pageArgs) (*gqlTermPage, error) {
+	return c.s.termPage(ctx, db.TermFilter{Category: c.id, Mask: search.TermFlag(args.Flags)}, args.Limit, args.Offset)
+}
+
+type gqlTag struct {
+	s    *Server
+	name string
+}
+
+func (t *gqlTag) Name() string { return t.name }
+
+func (t *gqlTag) Terms(ctx context.Context, args pageArgs) (*gqlTermPage, error) {
+	return t.s.termPage(ctx, db.TermFilter{Tags: []string{strings.ToLower(t.name)}, Mask: search.TermFlag(args.Flags)}, args.Limit, args.Offset)
+}
+
+type gqlExplanation struct {
+	e *db.Explanation
+}
+
+func gqlExplanations(e []*db.Explanation) []*gqlExplanation {
+	out := make([]*gqlExplanation, 0, len(e))
+	for _, i := range e {
+		out = append(out, &gqlExplanation{i})
+	}
+	return out
+}
+
+func (e *gqlExplanation) ID() int32             { return int32(e.e.ID) }
+func (e *gqlExplanation) Name() string          { return e.e.Name }
+func (e *gqlExplanation) Aliases() []string     { return nonNil(e.e.Aliases) }
+func (e *gqlExplanation) Description() string   { return e.e.Description }
+func (e *gqlExplanation) Created() graphql.Time { return graphql.Time{Time: e.e.Created} }
+
+type gqlPronounSet struct {
+	p *db.PronounSet
+}
+
+func gqlPronounSets(p []*db.PronounSet) []*gqlPronounSet {
+	out := make([]*gqlPronounSet, 0, len(p))
+	for _, i := range p {
+		out = append(out, &gqlPronounSet{i})
+	}
+	return out
+}
+
+func (p *gqlPronounSet) ID() int32                    { return int32(p.p.ID) }
+func (p *gqlPronounSet) Language() string             { return p.p.Lang() }
+func (p *gqlPronounSet) Forms() []string              { return p.p.FormList() }
+func (p *gqlPronounSet) Subjective() string           { return p.p.Subjective }
+func (p *gqlPronounSet) Objective() string            { return p.p.Objective }
+func (p *gqlPronounSet) PossessiveDeterminer() string { return p.p.PossDet }
+func (p *gqlPronounSet) PossessivePronoun() string    { return p.p.PossPro }
+func (p *gqlPronounSet) Reflexive() string            { return p.p.Reflexive }
+func (p *gqlPronounSet) Uses() int32                  { return int32(p.p.Uses) }
+func (p *gqlPronounSet) String() string               { return p.p.String() }
+
+type gqlContributorCategory struct {
+	name         string
+	contributors []*gqlContributor
+}
+
+func (c *gqlContributorCategory) Name() string                    { return c.name }
+func (c *gqlContributorCategory) Contributors() []*gqlContributor { return c.contributors }
+
+type gqlContributor struct {
+	name string
+}
+
+func (c *gqlContributor) Name() string { return c.name }
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
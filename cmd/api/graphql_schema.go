@@ -0,0 +1,98 @@
+package api
+
+const graphqlSchema = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	"Get a term by ID."
+	term(id: Int!): Term
+	"List terms, optionally filtered by category, tags (terms must have all of them), and flags (terms with any of these are excluded)."
+	terms(category: Int, tags: [String!], flags: Int = 8, limit: Int = 100, offset: Int = 0): TermPage!
+	"Search terms, optionally in a single category. Terms with any of the names in ignore are skipped."
+	search(input: String!, category: Int, limit: Int = 0, ignore: [String!]): [Term!]!
+
+	category(id: Int!): Category
+	categories: [Category!]!
+	tags: [Tag!]!
+
+	explanation(id: Int!): Explanation
+	explanations: [Explanation!]!
+
+	pronounSet(id: Int!): PronounSet
+	"List pronoun sets, optionally in a single language."
+	pronouns(language: String): [PronounSet!]!
+
+	contributors: [ContributorCategory!]!
+}
+
+type TermPage {
+	terms: [Term!]!
+	total: Int!
+}
+
+type Term {
+	id: Int!
+	name: String!
+	category: Category!
+	aliases: [String!]!
+	description: String!
+	note: String!
+	source: String!
+	created: Time!
+	lastModified: Time!
+	tags: [Tag!]!
+	contentWarnings: String!
+	imageURL: String!
+	flags: Int!
+	"Explanations that mention this term or one of its aliases."
+	explanations: [Explanation!]!
+	"Pronoun sets mentioned in this term's name, aliases, or description."
+	pronouns: [PronounSet!]!
+}
+
+type Category {
+	id: Int!
+	name: String!
+	terms(flags: Int = 8, limit: Int = 100, offset: Int = 0): TermPage!
+}
+
+type Tag {
+	name: String!
+	terms(flags: Int = 8, limit: Int = 100, offset: Int = 0): TermPage!
+}
+
+type Explanation {
+	id: Int!
+	name: String!
+	aliases: [String!]!
+	description: String!
+	created: Time!
+}
+
+type PronounSet {
+	id: Int!
+	language: String!
+	forms: [String!]!
+	subjective: String!
+	objective: String!
+	possessiveDeterminer: String!
+	possessivePronoun: String!
+	reflexive: String!
+	uses: Int!
+	"The set as a string, like she/her/her/hers/herself."
+	string: String!
+}
+
+type ContributorCategory {
+	name: String!
+	contributors: [Contributor!]!
+}
+
+type Contributor {
+	name: String!
+}
+`
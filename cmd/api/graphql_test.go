@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/types"
+)
+
+// execWithBudget runs query with the given complexity budget
+func execWithBudget(s *Server, query string, budget int) []string {
+	ctx := context.WithValue(context.Background(), complexityKey{}, &complexityBudget{left: budget})
+
+	var errs []string
+	for _, err := range s.gqlSchema.Exec(ctx, query, "", nil).Errors {
+		errs = append(errs, err.Message)
+	}
+	return errs
+}
+
+// TestGraphQLChargesEveryField checks that every top-level field is charged before it touches the database.
+// The server has no database, so a field that isn't charged first panics instead of returning errComplexity.
+func TestGraphQLChargesEveryField(t *testing.T) {
+	s := &Server{}
+	s.gqlSchema = s.newGraphQLSchema()
+
+	queries := map[string]string{
+		"term":         "term(id: 1) { id }",
+		"terms":        "terms { total }",
+		"search":       `search(input: "a") { id }`,
+		"category":     "category(id: 1) { id }",
+		"categories":   "categories { id }",
+		"tags":         "tags { name }",
+		"explanation":  "explanation(id: 1) { id }",
+		"explanations": "explanations { id }",
+		"pronounSet":   "pronounSet(id: 1) { id }",
+		"pronouns":     "pronouns { id }",
+		"contributors": "contributors { name }",
+	}
+
+	query, ok := s.gqlSchema.ASTSchema().Types["Query"].(*types.ObjectTypeDefinition)
+	if !ok {
+		t.Fatal("schema has no Query type")
+	}
+	for _, f := range query.Fields {
+		if _, ok := queries[f.Name]; !ok {
+			t.Errorf("no test query for field %q", f.Name)
+		}
+	}
+
+	for name, q := range queries {
+		errs := execWithBudget(s, "{ "+q+" }", 0)
+		if len(errs) != 1 || errs[0] != errComplexity.Error() {
+			t.Errorf("%v: expected %q, got %v", name, errComplexity, errs)
+		}
+	}
+}
+
+// TestGraphQLAliasedQueryOverLimit checks that aliasing a field many times doesn't get around the complexity limit
+func TestGraphQLAliasedQueryOverLimit(t *testing.T) {
+	s := &Server{}
+	s.gqlSchema = s.newGraphQLSchema()
+
+	const over = 5
+	n := graphqlMaxComplexity/queryCost + over
+
+	// an unknown language is rejected after the field is charged, so this never reaches the database
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, ` p%v: pronouns(language: "unknown") { id }`, i)
+	}
+	b.WriteString(" }")
+
+	var tooComplex int
+	for _, err := range execWithBudget(s, b.String(), graphqlMaxComplexity) {
+		if err == errComplexity.Error() {
+			tooComplex++
+		}
+	}
+	if tooComplex != over {
+		t.Fatalf("expected %v fields to go over the limit, got %v", over, tooComplex)
+	}
+}
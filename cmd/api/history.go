@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+type historyResponse struct {
+	Term      *db.Term      `json:"term"`
+	Revisions []db.Revision `json:"revisions"`
+}
+
+// termHistory is used for both /v1/term/{name}/history and /v2/terms/{id}/history
+func (s *Server) termHistory(w http.ResponseWriter, r *http.Request) {
+	param := chi.URLParam(r, "name")
+	if param == "" {
+		param = chi.URLParam(r, "id")
+	}
+
+	name, err := url.PathUnescape(param)
+	if err != nil || strings.TrimSpace(name) == "" {
+		writeStatus(w, r, http.StatusBadRequest, "name: invalid name")
+		return
+	}
+
+	m, err := s.termByNameOrID(name)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeStatus(w, r, http.StatusNotFound, "term not found")
+			return
+		}
+		log.Errorf("Error getting term %q: %v", name, err)
+		writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	revs, err := s.db.TermHistory(m.ID)
+	if err != nil {
+		log.Errorf("Error getting history for term %v: %v", m.ID, err)
+		writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	render.JSON(w, r, historyResponse{Term: m.Term, Revisions: revs})
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ReneKroon/ttlcache/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+type contextKey int
+
+const apiKeyContextKey contextKey = iota
+
+func apiKeyFromContext(ctx context.Context) *db.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey).(*db.APIKey)
+	return key
+}
+
+// authenticate checks the API key in the Authorization header, if there is one.
+// Requests without a key are allowed, but requests with an invalid key aren't.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := s.apiKey(r, token)
+		if err != nil {
+			if errors.Cause(err) == pgx.ErrNoRows {
+				writeStatus(w, r, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			if wait, ok := err.(errAuthRateLimited); ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Duration(wait).Seconds())))
+				writeStatus(w, r, http.StatusTooManyRequests, "rate limited")
+				return
+			}
+			log.Errorf("Error getting API key: %v", err)
+			writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey, key)))
+	})
+}
+
+// authLookupRateLimit is the number of uncached API key lookups per minute per IP.
+// Valid keys are cached, so this only limits guessing keys.
+const authLookupRateLimit = 10
+
+// errAuthRateLimited is returned by apiKey if the IP has looked up too many keys, with the time until it can try again
+type errAuthRateLimited time.Duration
+
+func (e errAuthRateLimited) Error() string {
+	return "too many API key lookups, try again in " + time.Duration(e).String()
+}
+
+// apiKey returns the API key for the token, cached for a minute.
+// Changed and revoked keys are removed from the cache by uncacheKey, the TTL only matters if the database listener is disconnected.
+// Invalid tokens are cached too, and looking up a token that isn't cached is rate limited per IP.
+func (s *Server) apiKey(r *http.Request, token string) (*db.APIKey, error) {
+	if v, err := s.keyCache.Get(token); err == nil {
+		return v.(*db.APIKey), nil
+	}
+	if _, err := s.invalidKeyCache.Get(token); err == nil {
+		return nil, pgx.ErrNoRows
+	}
+
+	if ok, _, wait := s.limiter.take("auth:"+remoteIP(r), authLookupRateLimit, 1); !ok {
+		return nil, errAuthRateLimited(wait)
+	}
+
+	key, err := s.db.APIKeyByToken(token)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			s.invalidKeyCache.Set(token, true)
+		}
+		return nil, err
+	}
+
+	s.keyCache.Set(token, &key)
+	return &key, nil
+}
+
+// uncacheKey removes a key from the cache when it's changed or revoked, from any process.
+// Changes could have been missed while the listener was disconnected, so every key is removed when it reconnects.
+func (s *Server) uncacheKey(n db.Notification) {
+	switch {
+	case n.Action == db.ReconnectAction:
+		s.keyCache.Purge()
+	case n.Table == db.APIKeysTable:
+		// the cache is keyed by token, and only the key's ID is in the notification
+		for _, token := range s.keyCache.GetKeys() {
+			if v, err := s.keyCache.Get(token); err == nil && v.(*db.APIKey).ID == n.ID() {
+				s.keyCache.Remove(token)
+			}
+		}
+	}
+}
+
+func newKeyCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(time.Minute)
+	c.SkipTTLExtensionOnHit(true)
+	c.SetCacheSizeLimit(1000)
+	return c
+}
+
+// newInvalidKeyCache returns the cache for invalid tokens.
+// It's separate from the key cache, so invalid tokens can't push valid keys out of it.
+func newInvalidKeyCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(10 * time.Second)
+	c.SkipTTLExtensionOnHit(true)
+	c.SetCacheSizeLimit(1000)
+	return c
+}
+
+type usageKey struct {
+	keyID    int // 0 for anonymous requests
+	endpoint string
+}
+
+// usageCounter counts requests in memory, and periodically writes them to the database
+type usageCounter struct {
+	mu     sync.Mutex
+	counts map[usageKey]int64
+}
+
+func (u *usageCounter) add(key *db.APIKey, endpoint string) {
+	k := usageKey{endpoint: endpoint}
+	if key != nil {
+		k.keyID = key.ID
+	}
+
+	u.mu.Lock()
+	if u.counts == nil {
+		u.counts = map[usageKey]int64{}
+	}
+	u.counts[k]++
+	u.mu.Unlock()
+}
+
+func (u *usageCounter) flush(d *db.DB) {
+	u.mu.Lock()
+	counts := u.counts
+	u.counts = nil
+	u.mu.Unlock()
+
+	for k, n := range counts {
+		var keyID *int
+		if k.keyID != 0 {
+			id := k.keyID
+			keyID = &id
+		}
+
+		err := d.AddAPIUsage(keyID, k.endpoint, n)
+		if err != nil {
+			log.Errorf("Error saving API usage for %v: %v", k.endpoint, err)
+		}
+	}
+}
+
+func (u *usageCounter) flushLoop(d *db.DB) {
+	for range time.Tick(time.Minute) {
+		u.flush(d)
+	}
+}
+
+// routePattern returns the pattern of the route that was matched, so usage isn't counted per term or search query
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return r.URL.Path
+	}
+	if p := rctx.RoutePattern(); p != "" {
+		return p
+	}
+	return r.URL.Path
+}
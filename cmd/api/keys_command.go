@@ -0,0 +1,186 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/db"
+	"github.com/urfave/cli/v2"
+)
+
+var keysCommand = &cli.Command{
+	Name:  "keys",
+	Usage: "Manage API keys",
+
+	Subcommands: []*cli.Command{
+		{
+			Name:      "create",
+			Usage:     "Create an API key",
+			ArgsUsage: "<name>",
+			Action:    createKey,
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    "rate-limit",
+					Aliases: []string{"r"},
+					Usage:   "Requests per minute (0 for the default)",
+				},
+				&cli.StringFlag{
+					Name:    "scope",
+					Aliases: []string{"s"},
+					Value:   string(db.ScopeRead),
+					Usage:   "What the key can do (read, director, or admin)",
+				},
+				&cli.StringFlag{
+					Name:    "owner",
+					Aliases: []string{"o"},
+					Usage:   "Discord user ID of the key's owner, changes made with the key are attributed to them",
+				},
+			},
+		},
+		{
+			Name:   "list",
+			Usage:  "List all API keys",
+			Action: listKeys,
+		},
+		{
+			Name:      "revoke",
+			Usage:     "Revoke an API key",
+			ArgsUsage: "<id>",
+			Action:    revokeKey,
+		},
+		{
+			Name:      "usage",
+			Usage:     "Show an API key's usage in the last 30 days",
+			ArgsUsage: "<id>",
+			Action:    keyUsage,
+		},
+	},
+}
+
+func keysDB() (*db.DB, error) {
+	c := common.ReadConfig()
+	return db.Init(c.Core.DatabaseURL)
+}
+
+func createKey(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return errors.Sentinel("no name provided")
+	}
+
+	var rateLimit *int
+	if n := c.Int("rate-limit"); n > 0 {
+		rateLimit = &n
+	}
+
+	scope := db.APIScope(c.String("scope"))
+	if !scope.Valid() {
+		return errors.Sentinel("invalid scope")
+	}
+
+	var owner discord.UserID
+	if s := c.String("owner"); s != "" {
+		sf, err := discord.ParseSnowflake(s)
+		if err != nil {
+			return errors.Sentinel("invalid owner ID")
+		}
+		owner = discord.UserID(sf)
+	}
+	if scope != db.ScopeRead && !owner.IsValid() {
+		return errors.Sentinel("keys that can edit the glossary need an owner")
+	}
+
+	d, err := keysDB()
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	key, token, err := d.CreateAPIKey(name, owner, scope, rateLimit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %v API key %v (%v) with ID %v.\n", key.Scope, key.Name, key.Prefix, key.ID)
+	fmt.Printf("Key: %v\n", token)
+	fmt.Println("This key will not be shown again!")
+	return nil
+}
+
+func listKeys(c *cli.Context) error {
+	d, err := keysDB()
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	keys, err := d.APIKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		status := "active"
+		if k.Revoked != nil {
+			status = "revoked " + k.Revoked.Format(time.RFC3339)
+		}
+		limit := "default"
+		if k.RateLimit != nil {
+			limit = strconv.Itoa(*k.RateLimit) + "/min"
+		}
+
+		fmt.Printf("%v\t%v\t%v...\tscope: %v\tlimit: %v\t%v\n", k.ID, k.Name, k.Prefix, k.Scope, limit, status)
+	}
+	return nil
+}
+
+func revokeKey(c *cli.Context) error {
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return errors.Sentinel("invalid key ID")
+	}
+
+	d, err := keysDB()
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	ok, err := d.RevokeAPIKey(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Sentinel("key not found or already revoked")
+	}
+
+	fmt.Printf("Revoked API key %v.\n", id)
+	return nil
+}
+
+func keyUsage(c *cli.Context) error {
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return errors.Sentinel("invalid key ID")
+	}
+
+	d, err := keysDB()
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	usage, err := d.APIKeyUsage(id, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return err
+	}
+
+	for _, u := range usage {
+		fmt.Printf("%v\t%v\t%v\n", u.Day.Format("2006-01-02"), u.Endpoint, u.Count)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/termora/berry/db"
+)
+
+// TestUncacheKey checks that a key's notification removes it from the cache, and leaves other keys
+func TestUncacheKey(t *testing.T) {
+	s := &Server{keyCache: newKeyCache()}
+	s.keyCache.Set("first", &db.APIKey{ID: 1})
+	s.keyCache.Set("second", &db.APIKey{ID: 2})
+
+	s.uncacheKey(db.Notification{Table: db.APIKeysTable, Action: db.UpdateAction, Key: "1"})
+	if _, err := s.keyCache.Get("first"); err == nil {
+		t.Fatal("expected the updated key to be removed from the cache")
+	}
+	if _, err := s.keyCache.Get("second"); err != nil {
+		t.Fatalf("expected the other key to still be cached, got %v", err)
+	}
+
+	s.uncacheKey(db.Notification{Action: db.ReconnectAction})
+	if n := s.keyCache.Count(); n != 0 {
+		t.Fatalf("expected the cache to be empty after reconnecting, got %v keys", n)
+	}
+}
@@ -21,7 +21,7 @@ func (s *Server) list(w http.ResponseWriter, r *http.Request) {
 		flags = search.TermFlag(f)
 	}
 
-	terms, err := s.db.GetTerms(flags)
+	terms, err := s.db.CachedTerms(flags)
 	if err != nil {
 		if errors.Cause(err) == pgx.ErrNoRows {
 			w.WriteHeader(http.StatusNotFound)
@@ -40,17 +40,20 @@ func (s *Server) listCategory(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
 
 	// get all terms from that category
-	terms, err := s.db.GetCategoryTerms(id, search.FlagListHidden)
+	all, err := s.db.CachedTerms(search.FlagListHidden)
 	if err != nil {
-		if errors.Cause(err) == pgx.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
 		log.Errorf("Error getting terms in category %v: %v", id, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	terms := []*db.Term{}
+	for _, t := range all {
+		if t.Category == id {
+			terms = append(terms, t)
+		}
+	}
+
 	render.JSON(w, r, terms)
 }
 
@@ -79,7 +82,13 @@ func (s *Server) explanations(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) pronouns(w http.ResponseWriter, r *http.Request) {
-	pronouns, err := s.db.Pronouns(db.AlphabeticPronounOrder)
+	lang := r.FormValue("language")
+	if _, ok := db.PronounLanguageByCode(lang); !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pronouns, err := s.db.LanguagePronouns(lang, db.AlphabeticPronounOrder)
 	if err != nil {
 		log.Errorf("Error getting pronouns: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1,16 +1,22 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 
+	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/termora/berry/commands/admin/auditlog"
 	"github.com/termora/berry/common"
+	"github.com/termora/berry/common/httpcache"
 	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/common/staff"
 	"github.com/termora/berry/db"
 	"github.com/termora/berry/db/search/typesense"
 	"github.com/urfave/cli/v2"
@@ -20,10 +26,28 @@ var Command = &cli.Command{
 	Name:   "api",
 	Usage:  "Run the API",
 	Action: run,
+
+	Subcommands: []*cli.Command{keysCommand, openAPICommand},
 }
 
 type Server struct {
 	db *db.DB
+
+	limiter         *limiter
+	usage           usageCounter
+	keyCache        *ttlcache.Cache
+	invalidKeyCache *ttlcache.Cache
+
+	// staff limits API keys to their owner's current roles
+	staff *staff.Checker
+
+	gqlSchema *graphql.Schema
+
+	// auditLog posts changes made through the API to the audit log channel
+	auditLog *auditlog.AuditLog
+
+	anonymousRateLimit int
+	keyRateLimit       int
 }
 
 func run(*cli.Context) (err error) {
@@ -32,7 +56,24 @@ func run(*cli.Context) (err error) {
 
 	log.Info("Loaded configuration file.")
 
-	s := &Server{}
+	s := &Server{
+		limiter:            newLimiter(),
+		keyCache:           newKeyCache(),
+		invalidKeyCache:    newInvalidKeyCache(),
+		staff:              staff.New(c),
+		anonymousRateLimit: c.API.AnonymousRateLimit,
+		keyRateLimit:       c.API.KeyRateLimit,
+	}
+	s.gqlSchema = s.newGraphQLSchema()
+	if s.staff == nil {
+		log.Warn("No support server configured, API keys' scopes won't be checked against their owner's roles")
+	}
+	if s.anonymousRateLimit <= 0 {
+		s.anonymousRateLimit = defaultAnonymousRateLimit
+	}
+	if s.keyRateLimit <= 0 {
+		s.keyRateLimit = defaultKeyRateLimit
+	}
 
 	// connect to the database
 	s.db, err = db.Init(c.Core.DatabaseURL)
@@ -40,6 +81,7 @@ func run(*cli.Context) (err error) {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
 	log.Info("Connected to database")
+	s.db.TermBaseURL = c.Bot.TermBaseURL()
 
 	if c.Core.TypesenseURL != "" && c.Core.TypesenseKey != "" {
 		s.db.Searcher, err = typesense.New(c.Core.TypesenseURL, c.Core.TypesenseKey, s.db.Pool)
@@ -48,26 +90,86 @@ func run(*cli.Context) (err error) {
 		}
 		log.Info("Connected to Typesense")
 	}
+	s.db.OnChange(s.uncacheKey)
+	s.db.Listen(context.Background())
+
+	s.auditLog = auditlog.NewStandalone(s.db, c)
+
+	go s.usage.flushLoop(s.db)
 
 	mx := chi.NewMux()
+	if c.API.BehindProxy {
+		mx.Use(middleware.RealIP)
+	}
 	mx.Use(middleware.Recoverer)
 	mx.Use(middleware.RedirectSlashes)
 	mx.Use(middleware.CleanPath)
+	mx.Use(cors)
 
 	mx.Route("/v1", func(r chi.Router) {
-		r.Get("/search/{term}", s.search)
-		r.Get(`/id/{id:\d+}`, s.term)
+		r.Use(s.authenticate)
 
-		r.Get("/list", s.list)
-		r.Get(`/list/{id:\d+}`, s.listCategory)
+		r.Group(func(r chi.Router) {
+			r.Use(s.rateLimit(1))
 
-		r.Get("/categories", s.categories)
-		r.Get("/explanations", s.explanations)
-		r.Get("/explanations", s.explanations)
-		r.Get("/tags", s.tags)
-		r.Get("/pronouns", s.pronouns)
+			r.Group(func(r chi.Router) {
+				r.Use(httpcache.Middleware(s.db, nil))
+
+				r.Get("/search/{term}", s.search)
+				r.Get(`/id/{id:\d+}`, s.term)
+				r.Get("/term/{name}", s.termByName)
+
+				r.Get(`/list/{id:\d+}`, s.listCategory)
+			})
+
+			r.Get("/categories", s.categories)
+			r.Get("/explanations", s.explanations)
+			r.Get("/tags", s.tags)
+			r.Get("/pronouns", s.pronouns)
+			r.Get("/pronouns/custom", s.customPronouns)
+			r.Get("/pronouns/*", s.renderPronouns)
+			r.Get("/languages", s.languages)
+			r.Get("/changes", s.changes)
+			// reasons can change without the term changing, so this can't be cached with the terms version
+			r.Get("/term/{name}/history", s.termHistory)
+
+			// writing to the glossary requires a key with the right scope
+			r.Group(func(r chi.Router) {
+				r.Use(s.requireScope(db.ScopeDirector))
+
+				r.Post("/terms", s.createTerm)
+				r.Patch(`/terms/{id:\d+}`, s.updateTerm)
+
+				r.Post("/tags", s.createTag)
+				r.Patch("/tags/{tag}", s.updateTag)
+
+				r.Post("/explanations", s.createExplanation)
+				r.Patch(`/explanations/{id:\d+}`, s.updateExplanation)
+
+				r.Post("/pronouns", s.createPronouns)
+				r.Patch(`/pronouns/{id:\d+}`, s.updatePronouns)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(s.requireScope(db.ScopeAdmin))
+
+				r.Delete(`/terms/{id:\d+}`, s.deleteTerm)
+				r.Delete("/tags/{tag}", s.deleteTag)
+				r.Delete(`/explanations/{id:\d+}`, s.deleteExplanation)
+				r.Delete(`/pronouns/{id:\d+}`, s.deletePronouns)
+
+				r.Post("/categories", s.createCategory)
+				r.Patch(`/categories/{id:\d+}`, s.updateCategory)
+				r.Delete(`/categories/{id:\d+}`, s.deleteCategory)
+			})
+		})
+
+		// this returns every term, so it counts as more than one request
+		r.With(s.rateLimit(listCost), httpcache.Middleware(s.db, nil)).Get("/list", s.list)
 	})
 
+	mx.Route("/v2", s.mountV2)
+
 	mx.Get("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte(`User-agent: *
 Disallow: /`))
@@ -97,6 +199,7 @@ Disallow: /`))
 	select {
 	case <-sc:
 		log.Infof("Interrupt signal received. Shutting down...")
+		s.usage.flush(s.db)
 		s.db.Close()
 	case err := <-e:
 		log.Errorf("Error serving API: %v", err)
@@ -0,0 +1,542 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/common/httpcache"
+	"github.com/termora/berry/db"
+	"github.com/urfave/cli/v2"
+)
+
+// param is a path or query parameter
+type param struct {
+	Name        string
+	In          string // "path" or "query"
+	Type        string // an OpenAPI type, like "string" or "integer"
+	Description string
+	Required    bool
+}
+
+// endpoint is a single v2 endpoint.
+// The v2 routes and the OpenAPI document are both generated from the list of endpoints, so they can't get out of sync.
+type endpoint struct {
+	Method  string
+	Path    string // chi route pattern
+	Summary string
+
+	// Scope is the API key scope needed, or empty if the endpoint doesn't need a key
+	Scope db.APIScope
+	// Cost is the number of requests this counts as for rate limiting, 0 for the default of 1
+	Cost int
+	// Cached is true if the response only changes when terms do, so it gets an ETag and Last-Modified
+	Cached bool
+
+	Params []param
+	// Body is the request body type (as a zero value), nil for no body
+	Body interface{}
+	// Status is the status code on success, 0 for 200
+	Status int
+	// Response is the response type (as a zero value), nil for no content
+	Response interface{}
+
+	Handler http.HandlerFunc
+}
+
+var (
+	pageParams = []param{
+		{Name: "limit", In: "query", Type: "integer", Description: "The maximum number of results, between 1 and 500. Defaults to 100."},
+		{Name: "offset", In: "query", Type: "integer", Description: "The number of results to skip."},
+	}
+	idParamSpec = param{Name: "id", In: "path", Type: "integer", Required: true}
+)
+
+func (s *Server) v2Endpoints() []endpoint {
+	return []endpoint{
+		{
+			Method: http.MethodGet, Path: "/terms", Summary: "List terms",
+			Params: append([]param{
+				{Name: "category", In: "query", Type: "integer", Description: "Only return terms in this category."},
+				{Name: "tags", In: "query", Type: "string", Description: "Comma-separated tags, only return terms with all of them."},
+				{Name: "flags", In: "query", Type: "integer", Description: "Exclude terms with any of these flags. Defaults to 8 (hidden from lists)."},
+			}, pageParams...),
+			Response: termPage{},
+			Cached:   true,
+			Handler:  s.v2Terms,
+		},
+		{
+			Method: http.MethodGet, Path: `/terms/{id:\d+}`, Summary: "Get a term",
+			Params:   []param{idParamSpec},
+			Response: db.Term{},
+			Cached:   true,
+			Handler:  s.v2Term,
+		},
+		{
+			Method: http.MethodGet, Path: "/terms/by-name/{name}", Summary: "Get a term by name or alias",
+			Params: []param{
+				{Name: "name", In: "path", Type: "string", Required: true, Description: "The term's name or one of its aliases, ignoring case and diacritics."},
+			},
+			Response: namedTerm{},
+			Cached:   true,
+			Handler:  s.termByName,
+		},
+		{
+			Method: http.MethodGet, Path: `/terms/{id:\d+}/history`, Summary: "Get a term's public revisions",
+			Params:   []param{idParamSpec},
+			Response: historyResponse{},
+			Handler:  s.termHistory,
+		},
+		{
+			Method: http.MethodPost, Path: "/terms", Summary: "Create a term",
+			Scope: db.ScopeDirector, Body: termRequest{}, Status: http.StatusCreated, Response: db.Term{},
+			Handler: s.createTerm,
+		},
+		{
+			Method: http.MethodPatch, Path: `/terms/{id:\d+}`, Summary: "Update a term",
+			Scope: db.ScopeDirector, Params: []param{idParamSpec}, Body: termRequest{}, Response: db.Term{},
+			Handler: s.updateTerm,
+		},
+		{
+			Method: http.MethodDelete, Path: `/terms/{id:\d+}`, Summary: "Delete a term",
+			Scope: db.ScopeAdmin, Params: []param{idParamSpec}, Status: http.StatusNoContent,
+			Handler: s.deleteTerm,
+		},
+		{
+			Method: http.MethodGet, Path: "/search", Summary: "Search terms",
+			Params: []param{
+				{Name: "q", In: "query", Type: "string", Required: true, Description: "The search query."},
+				{Name: "category", In: "query", Type: "string", Description: "Only return terms in this category, by ID or name."},
+				{Name: "ignore", In: "query", Type: "string", Description: "Comma-separated tags, terms with any of these are skipped."},
+				{Name: "no_cw", In: "query", Type: "boolean", Description: "Skip terms with content warnings."},
+				{Name: "highlight", In: "query", Type: "boolean", Description: "Whether to return headlines. Defaults to true."},
+				{Name: "limit", In: "query", Type: "integer", Description: "The maximum number of results, at most 500. Defaults to 50."},
+				{Name: "offset", In: "query", Type: "integer", Description: "The number of results to skip."},
+			},
+			Response: []searchResult{},
+			Cached:   true,
+			Handler:  s.v2Search,
+		},
+		{
+			Method: http.MethodGet, Path: "/categories", Summary: "List categories",
+			Response: []db.Category{},
+			Handler:  s.v2Categories,
+		},
+		{
+			Method: http.MethodPost, Path: "/categories", Summary: "Create a category",
+			Scope: db.ScopeAdmin, Body: nameRequest{}, Status: http.StatusCreated, Response: db.Category{},
+			Handler: s.createCategory,
+		},
+		{
+			Method: http.MethodPatch, Path: `/categories/{id:\d+}`, Summary: "Rename a category",
+			Scope: db.ScopeAdmin, Params: []param{idParamSpec}, Body: nameRequest{}, Response: db.Category{},
+			Handler: s.updateCategory,
+		},
+		{
+			Method: http.MethodDelete, Path: `/categories/{id:\d+}`, Summary: "Delete an empty category",
+			Scope: db.ScopeAdmin, Params: []param{idParamSpec}, Status: http.StatusNoContent,
+			Handler: s.deleteCategory,
+		},
+		{
+			Method: http.MethodGet, Path: "/tags", Summary: "List tags",
+			Response: []string{},
+			Handler:  s.v2Tags,
+		},
+		{
+			Method: http.MethodPost, Path: "/tags", Summary: "Create a tag",
+			Scope: db.ScopeDirector, Body: nameRequest{}, Status: http.StatusCreated, Response: nameRequest{},
+			Handler: s.createTag,
+		},
+		{
+			Method: http.MethodPatch, Path: "/tags/{tag}", Summary: "Rename a tag on every term",
+			Scope:  db.ScopeDirector,
+			Params: []param{{Name: "tag", In: "path", Type: "string", Required: true}},
+			Body:   nameRequest{}, Response: nameRequest{},
+			Handler: s.updateTag,
+		},
+		{
+			Method: http.MethodDelete, Path: "/tags/{tag}", Summary: "Remove a tag from every term",
+			Scope:   db.ScopeAdmin,
+			Params:  []param{{Name: "tag", In: "path", Type: "string", Required: true}},
+			Status:  http.StatusNoContent,
+			Handler: s.deleteTag,
+		},
+		{
+			Method: http.MethodGet, Path: "/explanations", Summary: "List explanations",
+			Response: []db.Explanation{},
+			Handler:  s.v2Explanations,
+		},
+		{
+			Method: http.MethodPost, Path: "/explanations", Summary: "Create an explanation",
+			Scope: db.ScopeDirector, Body: explanationRequest{}, Status: http.StatusCreated, Response: db.Explanation{},
+			Handler: s.createExplanation,
+		},
+		{
+			Method: http.MethodPatch, Path: `/explanations/{id:\d+}`, Summary: "Update an explanation",
+			Scope: db.ScopeDirector, Params: []param{idParamSpec}, Body: explanationRequest{}, Response: db.Explanation{},
+			Handler: s.updateExplanation,
+		},
+		{
+			Method: http.MethodDelete, Path: `/explanations/{id:\d+}`, Summary: "Delete an explanation",
+			Scope: db.ScopeAdmin, Params: []param{idParamSpec}, Status: http.StatusNoContent,
+			Handler: s.deleteExplanation,
+		},
+		{
+			Method: http.MethodGet, Path: "/pronouns", Summary: "List pronoun sets",
+			Params: []param{
+				{Name: "language", In: "query", Type: "string", Description: "Only return sets in this language."},
+			},
+			Response: []db.PronounSet{},
+			Handler:  s.v2Pronouns,
+		},
+		{
+			Method: http.MethodGet, Path: "/pronouns/render", Summary: "Render example sentences for pronouns",
+			Params: []param{
+				{Name: "pronouns", In: "query", Type: "string", Required: true, Description: "The pronouns, like `she/her`, or mixed sets like `she/they`."},
+				{Name: "language", In: "query", Type: "string", Description: "The pronouns' language. Defaults to English."},
+				{Name: "name", In: "query", Type: "string", Description: "A name to use in the examples."},
+			},
+			Response: renderedPronouns{},
+			Handler:  s.v2RenderPronouns,
+		},
+		{
+			Method: http.MethodGet, Path: "/pronouns/custom", Summary: "Render example sentences for any forms",
+			Params: []param{
+				{Name: "forms", In: "query", Type: "string", Required: true, Description: "The forms separated with `/`, like `ze/zir/zir/zirs/zirself`. Mix sets by repeating the parameter."},
+				{Name: "language", In: "query", Type: "string", Description: "The forms' language. Defaults to English."},
+				{Name: "name", In: "query", Type: "string", Description: "A name to use in the examples."},
+			},
+			Response: renderedPronouns{},
+			Handler:  s.customPronouns,
+		},
+		{
+			Method: http.MethodPost, Path: "/pronouns", Summary: "Create a pronoun set",
+			Scope: db.ScopeDirector, Body: pronounRequest{}, Status: http.StatusCreated, Response: db.PronounSet{},
+			Handler: s.createPronouns,
+		},
+		{
+			Method: http.MethodPatch, Path: `/pronouns/{id:\d+}`, Summary: "Update a pronoun set",
+			Scope: db.ScopeDirector, Params: []param{idParamSpec}, Body: pronounRequest{}, Response: db.PronounSet{},
+			Handler: s.updatePronouns,
+		},
+		{
+			Method: http.MethodDelete, Path: `/pronouns/{id:\d+}`, Summary: "Delete a pronoun set",
+			Scope: db.ScopeAdmin, Params: []param{idParamSpec}, Status: http.StatusNoContent,
+			Handler: s.deletePronouns,
+		},
+		{
+			Method: http.MethodGet, Path: "/languages", Summary: "List pronoun languages",
+			Response: []db.PronounLanguage{},
+			Handler:  s.v2Languages,
+		},
+		{
+			Method: http.MethodGet, Path: "/changes", Summary: "Get changes since a cursor",
+			Params: []param{
+				{Name: "since", In: "query", Type: "string", Description: "The cursor returned by the previous request."},
+				{Name: "limit", In: "query", Type: "integer", Description: "The maximum number of changes, between 1 and 500. Defaults to 100."},
+			},
+			Response: changesResponse{},
+			Handler:  s.changes,
+		},
+		{
+			Method: http.MethodPost, Path: "/graphql", Summary: "Run a GraphQL query",
+			Cost: graphqlRequestCost, Body: graphqlRequest{}, Response: map[string]interface{}{},
+			Handler: s.graphql,
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json", Summary: "Get this document",
+			Response: map[string]interface{}{},
+			Handler:  s.openAPI,
+		},
+	}
+}
+
+// mountV2 adds all v2 endpoints to the router
+func (s *Server) mountV2(r chi.Router) {
+	r.Use(v2Context)
+	r.Use(s.authenticate)
+	r.NotFound(notFound)
+	r.MethodNotAllowed(methodNotAllowed)
+
+	for _, e := range s.v2Endpoints() {
+		cost := e.Cost
+		if cost == 0 {
+			cost = 1
+		}
+
+		mw := chi.Middlewares{s.rateLimit(cost)}
+		if e.Scope != "" {
+			mw = append(mw, s.requireScope(e.Scope))
+		}
+		if e.Cached {
+			mw = append(mw, httpcache.Middleware(s.db, nil))
+		}
+
+		r.With(mw...).Method(e.Method, e.Path, e.Handler)
+	}
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, s.openAPIDocument())
+}
+
+var routeParamRegexp = regexp.MustCompile(`\{(\w+):[^}]+\}`)
+
+// openAPIDocument returns the OpenAPI 3 document for the v2 API
+func (s *Server) openAPIDocument() map[string]interface{} {
+	g := &schemaGen{components: map[string]interface{}{}}
+	errResponse := func(desc string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": desc,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(errorEnvelope{}))},
+			},
+		}
+	}
+
+	paths := map[string]map[string]interface{}{}
+	for _, e := range s.v2Endpoints() {
+		path := routeParamRegexp.ReplaceAllString(e.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+
+		op := map[string]interface{}{
+			"summary":     e.Summary,
+			"operationId": operationID(e.Method, path),
+		}
+
+		if len(e.Params) > 0 {
+			var params []interface{}
+			for _, p := range e.Params {
+				v := map[string]interface{}{
+					"name":     p.Name,
+					"in":       p.In,
+					"required": p.Required,
+					"schema":   map[string]interface{}{"type": p.Type},
+				}
+				if p.Description != "" {
+					v["description"] = p.Description
+				}
+				params = append(params, v)
+			}
+			op["parameters"] = params
+		}
+
+		if e.Body != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(e.Body))},
+				},
+			}
+		}
+
+		status := e.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]interface{}{"description": http.StatusText(status)}
+		if e.Response != nil {
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(e.Response))},
+			}
+		}
+
+		responses := map[string]interface{}{
+			strconv.Itoa(status): success,
+			"400":                errResponse("Invalid parameters or body"),
+			"429":                errResponse("Rate limited"),
+		}
+		if strings.Contains(path, "{") {
+			responses["404"] = errResponse("Not found")
+		}
+		if e.Scope != "" {
+			responses["401"] = errResponse("No API key")
+			responses["403"] = errResponse("The API key doesn't have the " + string(e.Scope) + " scope")
+			op["security"] = []interface{}{map[string]interface{}{"apiKey": []string{}}}
+			op["description"] = "Requires an API key with the `" + string(e.Scope) + "` scope."
+		}
+		op["responses"] = responses
+
+		paths[path][strings.ToLower(e.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Termora API",
+			"version": "2 (" + common.Version + ")",
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"url": "/v2"},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.components,
+			"securitySchemes": map[string]interface{}{
+				"apiKey": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "Authorization",
+				},
+			},
+		},
+	}
+}
+
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.Split(path, "/") {
+		part = strings.Trim(part, "{}")
+		part = strings.Map(func(r rune) rune {
+			if r == '.' || r == '-' {
+				return -1
+			}
+			return r
+		}, part)
+		if part != "" {
+			id += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return id
+}
+
+// schemaGen generates JSON schemas from Go types, using their JSON tags
+type schemaGen struct {
+	components map[string]interface{}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (g *schemaGen) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType, t.Kind() == reflect.Interface:
+		return map[string]interface{}{"nullable": true}
+	case t.Kind() != reflect.Struct && t.Implements(marshalerType):
+		// snowflakes and similar types are encoded as strings
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schema(t.Elem())
+		if _, ok := s["$ref"]; ok {
+			return map[string]interface{}{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if _, ok := g.components[name]; !ok {
+			// add a placeholder first, in case the type refers to itself
+			g.components[name] = nil
+			g.components[name] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func (g *schemaGen) object(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	var required []string
+	g.fields(t, props, &required)
+	sort.Strings(required)
+
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (g *schemaGen) fields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i != -1 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, props, required)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// componentName returns a name for a struct type, like "Term" or "TermPage"
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+var openAPICommand = &cli.Command{
+	Name:  "openapi",
+	Usage: "Print the OpenAPI document for the v2 API",
+	Action: func(c *cli.Context) error {
+		b, err := json.MarshalIndent((&Server{}).openAPIDocument(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	},
+}
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+var pathPlaceholderRegexp = regexp.MustCompile(`\{(\w+)(?::[^}]+)?\}`)
+
+// TestOpenAPIPathParams checks that every placeholder in an endpoint's path is documented as a path parameter, and the other way around
+func TestOpenAPIPathParams(t *testing.T) {
+	s := &Server{}
+
+	for _, e := range s.v2Endpoints() {
+		inPath := map[string]bool{}
+		for _, m := range pathPlaceholderRegexp.FindAllStringSubmatch(e.Path, -1) {
+			inPath[m[1]] = true
+		}
+
+		documented := map[string]bool{}
+		for _, p := range e.Params {
+			if p.In == "path" {
+				documented[p.Name] = true
+			}
+		}
+
+		for _, name := range sortedKeys(inPath) {
+			if !documented[name] {
+				t.Errorf("%v %v: path parameter %q isn't documented", e.Method, e.Path, name)
+			}
+		}
+		for _, name := range sortedKeys(documented) {
+			if !inPath[name] {
+				t.Errorf("%v %v: documented path parameter %q isn't in the path", e.Method, e.Path, name)
+			}
+		}
+	}
+}
+
+// TestOpenAPIQueryParams checks that every query parameter a v2 handler reads is documented, and that every documented one is read.
+// The handlers are found in this package's source, following calls to other functions in the package (like intParam).
+func TestOpenAPIQueryParams(t *testing.T) {
+	reads, err := parseParamReads(".")
+	if err != nil {
+		t.Fatalf("parsing package: %v", err)
+	}
+
+	s := &Server{}
+	for _, e := range s.v2Endpoints() {
+		name := handlerName(e.Handler)
+		if !reads.has(name) {
+			t.Errorf("%v %v: couldn't find handler %q", e.Method, e.Path, name)
+			continue
+		}
+
+		read, unresolved := reads.queryParams(name)
+		for _, call := range unresolved {
+			t.Errorf("%v %v: can't tell which query parameter %v reads", e.Method, e.Path, call)
+		}
+
+		documented := map[string]bool{}
+		for _, p := range e.Params {
+			if p.In == "query" {
+				documented[p.Name] = true
+			}
+		}
+
+		for _, p := range sortedKeys(read) {
+			if !documented[p] {
+				t.Errorf("%v %v: %v reads query parameter %q, but it isn't documented", e.Method, e.Path, name, p)
+			}
+		}
+		for _, p := range sortedKeys(documented) {
+			if !read[p] {
+				t.Errorf("%v %v: query parameter %q is documented, but %v never reads it", e.Method, e.Path, p, name)
+			}
+		}
+	}
+}
+
+// TestOpenAPIRefs checks that every $ref in the document points to an existing schema
+func TestOpenAPIRefs(t *testing.T) {
+	b, err := json.Marshal((&Server{}).openAPIDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if len(schemas) == 0 {
+		t.Fatal("document has no schemas")
+	}
+
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				if name == ref || schemas[name] == nil {
+					t.Errorf("%v: $ref %q doesn't point to a schema", path, ref)
+				}
+			}
+			for k, v := range v {
+				walk(path+"/"+k, v)
+			}
+		case []interface{}:
+			for i, v := range v {
+				walk(path+"/"+strconv.Itoa(i), v)
+			}
+		}
+	}
+	walk("#", doc)
+}
+
+// TestOpenAPIValid checks that /v2/openapi.json is a valid OpenAPI 3 document
+func TestOpenAPIValid(t *testing.T) {
+	s := &Server{limiter: newLimiter(), anonymousRateLimit: defaultAnonymousRateLimit}
+
+	mx := chi.NewMux()
+	mx.Route("/v2", s.mountV2)
+
+	rec := httptest.NewRecorder()
+	mx.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %v: %v", rec.Code, rec.Body)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("loading document: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("invalid document: %v", err)
+	}
+}
+
+// handlerName returns the name of a handler as it's keyed in paramReads, like "Server.v2Terms"
+func handlerName(h http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimPrefix(name[strings.Index(name, ".")+1:], "(*")
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.Replace(name, ")", "", 1)
+}
+
+// paramReads is every function and method in a package, by name
+type paramReads struct {
+	funcs map[string]*ast.FuncDecl
+}
+
+func parseParamReads(dir string) (*paramReads, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &paramReads{funcs: map[string]*ast.FuncDecl{}}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				if fn, ok := d.(*ast.FuncDecl); ok {
+					p.funcs[funcKey(fn)] = fn
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+func (p *paramReads) has(name string) bool {
+	_, ok := p.funcs[name]
+	return ok
+}
+
+// queryParams returns the query parameters read by the named function and everything it calls in the package.
+// unresolved has the calls whose parameter name isn't a constant.
+func (p *paramReads) queryParams(name string) (read map[string]bool, unresolved []string) {
+	read = map[string]bool{}
+	visited := map[string]bool{}
+
+	var collect func(key string, args map[string]string)
+	collect = func(key string, args map[string]string) {
+		fn := p.funcs[key]
+		if fn == nil || fn.Body == nil {
+			return
+		}
+		v := key + fmtArgs(args)
+		if visited[v] {
+			return
+		}
+		visited[v] = true
+
+		var recv, recvType string
+		if fn.Recv != nil && len(fn.Recv.List[0].Names) > 0 {
+			recv = fn.Recv.List[0].Names[0].Name
+			recvType = strings.TrimSuffix(key, "."+fn.Name.Name)
+		}
+
+		value := func(e ast.Expr) (string, bool) {
+			switch e := e.(type) {
+			case *ast.BasicLit:
+				if e.Kind == token.STRING {
+					s, err := strconv.Unquote(e.Value)
+					return s, err == nil
+				}
+			case *ast.Ident:
+				s, ok := args[e.Name]
+				return s, ok
+			}
+			return "", false
+		}
+		addRead := func(call string, e ast.Expr) {
+			if v, ok := value(e); ok {
+				read[v] = true
+			} else {
+				unresolved = append(unresolved, call+" in "+key)
+			}
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IndexExpr:
+				// r.Form["name"]
+				if sel, ok := n.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Form" {
+					addRead("Form[...]", n.Index)
+				}
+			case *ast.CallExpr:
+				var callee string
+				switch fun := n.Fun.(type) {
+				case *ast.Ident:
+					callee = fun.Name
+				case *ast.SelectorExpr:
+					switch {
+					case fun.Sel.Name == "FormValue" && len(n.Args) == 1:
+						addRead("FormValue", n.Args[0])
+						return true
+					case fun.Sel.Name == "Get" && len(n.Args) == 1 && isQueryCall(fun.X):
+						addRead("Query().Get", n.Args[0])
+						return true
+					}
+					if id, ok := fun.X.(*ast.Ident); ok && recv != "" && id.Name == recv {
+						callee = recvType + "." + fun.Sel.Name
+					}
+				}
+
+				calleeFn := p.funcs[callee]
+				if calleeFn == nil {
+					return true
+				}
+
+				// pass on the constant arguments, so helpers like intParam(w, r, "limit", ...) are followed
+				bound := map[string]string{}
+				i := 0
+				for _, field := range calleeFn.Type.Params.List {
+					for _, name := range field.Names {
+						if i < len(n.Args) {
+							if v, ok := value(n.Args[i]); ok {
+								bound[name.Name] = v
+							}
+						}
+						i++
+					}
+					if len(field.Names) == 0 {
+						i++
+					}
+				}
+				collect(callee, bound)
+			}
+			return true
+		})
+	}
+
+	collect(name, nil)
+	return read, unresolved
+}
+
+// isQueryCall returns true if e is a call like r.URL.Query()
+func isQueryCall(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Query"
+}
+
+func fmtArgs(args map[string]string) string {
+	var s []string
+	for k, v := range args {
+		s = append(s, k+"="+v)
+	}
+	sort.Strings(s)
+	return "(" + strings.Join(s, ",") + ")"
+}
+
+func sortedKeys(m map[string]bool) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
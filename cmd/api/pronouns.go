@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/pronouns/examples"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+type renderedPronouns struct {
+	Sets     []*db.PronounSet `json:"sets"`
+	Name     string           `json:"name,omitempty"`
+	Examples []string         `json:"examples"`
+}
+
+func (s *Server) renderPronouns(w http.ResponseWriter, r *http.Request) {
+	input := strings.Trim(chi.URLParam(r, "*"), "/")
+	if input == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	lang, ok := db.PronounLanguageByCode(r.FormValue("language"))
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	sets, mixed, err := s.db.ParsePronouns(lang.Code, input)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err == db.ErrTooManyForms {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Errorf("Error getting pronouns %q: %v", input, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// if the input matches more than one set, use the first one, same as the bot does for mixed sets
+	if !mixed {
+		sets = sets[:1]
+	}
+
+	name := r.FormValue("name")
+
+	ex, err := examples.Render(sets, name)
+	if err != nil {
+		log.Errorf("Error rendering pronouns %q: %v", input, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, renderedPronouns{
+		Sets:     sets,
+		Name:     name,
+		Examples: ex,
+	})
+}
+
+// customPronouns renders arbitrary forms, without looking them up in the database
+func (s *Server) customPronouns(w http.ResponseWriter, r *http.Request) {
+	lang, ok := db.PronounLanguageByCode(r.FormValue("language"))
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "language: unknown language")
+		return
+	}
+
+	// sets can be given as separate forms parameters, or separated with + like in the bot's custom command
+	var input []string
+	for _, v := range r.Form["forms"] {
+		for _, forms := range strings.Split(v, "+") {
+			if forms = strings.Trim(forms, "/ "); forms != "" {
+				input = append(input, forms)
+			}
+		}
+	}
+	if len(input) == 0 {
+		writeError(w, r, http.StatusBadRequest, "forms: can't be empty")
+		return
+	}
+
+	var sets []*db.PronounSet
+	for _, forms := range input {
+		set, err := db.NewPronounSet(lang.Code, strings.Split(forms, "/"))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("forms: every set must have exactly %v forms", len(lang.Slots)))
+			return
+		}
+		sets = append(sets, &set)
+	}
+
+	name := r.FormValue("name")
+
+	ex, err := examples.Render(sets, name)
+	if err != nil {
+		log.Errorf("Error rendering custom pronouns %q: %v", input, err)
+		writeError(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	render.JSON(w, r, renderedPronouns{
+		Sets:     sets,
+		Name:     name,
+		Examples: ex,
+	})
+}
+
+func (s *Server) languages(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, db.PronounLanguages)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default rate limits, in requests per minute
+const (
+	defaultAnonymousRateLimit = 30
+	defaultKeyRateLimit       = 300
+)
+
+// listCost is the number of requests a call to /list counts as, as it returns the whole database
+const listCost = 10
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter is a token bucket rate limiter. Each bucket holds up to limit tokens, and refills at limit tokens per minute.
+type limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newLimiter() *limiter {
+	l := &limiter{buckets: map[string]*bucket{}}
+	go l.cleanup()
+	return l
+}
+
+// take takes n tokens from the named bucket.
+// It returns whether the tokens could be taken, how many are left, and how long until the bucket is full again (or, if ok is false, until n tokens are available).
+func (l *limiter) take(name string, limit, n int) (ok bool, remaining int, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	rate := float64(limit) / time.Minute.Seconds()
+
+	b, exists := l.buckets[name]
+	if !exists {
+		b = &bucket{tokens: float64(limit), last: now}
+		l.buckets[name] = b
+	}
+
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens < float64(n) {
+		return false, int(b.tokens), secondsDuration((float64(n) - b.tokens) / rate)
+	}
+
+	b.tokens -= float64(n)
+	return true, int(b.tokens), secondsDuration((float64(limit) - b.tokens) / rate)
+}
+
+// cleanup removes buckets that haven't been used in a while, as those would be full anyway
+func (l *limiter) cleanup() {
+	for range time.Tick(10 * time.Minute) {
+		l.mu.Lock()
+		for name, b := range l.buckets {
+			if time.Since(b.last) > time.Minute {
+				delete(l.buckets, name)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
+
+// rateLimit limits requests per API key, or per IP for anonymous requests, and counts usage.
+// Each request takes cost tokens.
+func (s *Server) rateLimit(cost int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := s.anonymousRateLimit
+			name := "ip:" + remoteIP(r)
+
+			key := apiKeyFromContext(r.Context())
+			if key != nil {
+				limit = s.keyRateLimit
+				if key.RateLimit != nil {
+					limit = *key.RateLimit
+				}
+				name = "key:" + strconv.Itoa(key.ID)
+			}
+
+			ok, remaining, wait := s.limiter.take(name, limit, cost)
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(wait).Unix(), 10))
+
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())))
+				writeStatus(w, r, http.StatusTooManyRequests, "rate limited")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			s.usage.add(key, routePattern(r))
+		})
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -2,30 +2,160 @@ package api
 
 import (
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
 	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
 )
 
-func (s *Server) search(w http.ResponseWriter, r *http.Request) {
-	query := chi.URLParam(r, "term")
+const defaultSearchLimit = 50
+
+var numberRegex = regexp.MustCompile(`^\d+$`)
+
+// searchResult is a term returned from a search, with its [[links]] resolved
+type searchResult struct {
+	*db.Term
+	// Links are all the links in the term's description and note
+	Links []db.TermLink `json:"links"`
+}
+
+// searchTerms runs a search with the options in the request's query parameters.
+// ok is false if an error was already written.
+func (s *Server) searchTerms(w http.ResponseWriter, r *http.Request, query string) (results []searchResult, ok bool) {
+	limit, ok := intParam(w, r, "limit", defaultSearchLimit, 1, maxPageLimit)
+	if !ok {
+		return nil, false
+	}
+	offset, ok := intParam(w, r, "offset", 0, 0, 0)
+	if !ok {
+		return nil, false
+	}
+
+	highlight := true
+	if v := r.FormValue("highlight"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "highlight: must be true or false")
+			return nil, false
+		}
+		highlight = b
+	}
+
+	noCW := false
+	if v := r.FormValue("no_cw"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "no_cw: must be true or false")
+			return nil, false
+		}
+		noCW = b
+	}
+
+	ignore := []string{}
+	for _, t := range strings.Split(r.FormValue("ignore"), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			ignore = append(ignore, t)
+		}
+	}
+
+	// same as the bot, if the query starts with !, only return the first result
+	if strings.HasPrefix(query, "!") {
+		query = strings.TrimSpace(strings.TrimPrefix(query, "!"))
+		limit, offset = 1, 0
+	}
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, "query can't be empty")
+		return nil, false
+	}
+
+	// CW filtering happens after the search, so fetch as many terms as we can
+	fetch := offset + limit
+	if noCW || fetch > maxPageLimit {
+		fetch = maxPageLimit
+	}
+
+	var (
+		terms []*db.Term
+		err   error
+	)
+	if cat := r.FormValue("category"); cat != "" {
+		id, err := strconv.Atoi(cat)
+		if err != nil {
+			id, err = s.db.CategoryID(cat)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "category: category not found")
+				return nil, false
+			}
+		}
 
-	terms, err := s.db.Search(query, 0, []string{})
+		terms, err = s.db.SearchCat(query, id, fetch, ignore)
+	} else {
+		terms, err = s.db.Search(query, fetch, ignore)
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		log.Errorf("Error searching for %q: %v", query, err)
+		writeError(w, r, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+
+	if noCW {
+		filter := []*db.Term{}
+		for _, t := range terms {
+			if t.ContentWarnings == "" {
+				filter = append(filter, t)
+			}
+		}
+		terms = filter
+	}
+
+	if offset >= len(terms) {
+		return []searchResult{}, true
+	}
+	terms = terms[offset:]
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+
+	results = make([]searchResult, 0, len(terms))
+	for _, t := range terms {
+		if !highlight {
+			t.Headline = ""
+		}
+
+		links, err := s.db.TermLinks(t.Description + "\n" + t.Note)
+		if err != nil {
+			log.Errorf("Error resolving links for term %v: %v", t.ID, err)
+			writeError(w, r, http.StatusInternalServerError, "internal server error")
+			return nil, false
+		}
+		if links == nil {
+			links = []db.TermLink{}
+		}
+
+		results = append(results, searchResult{Term: t, Links: links})
+	}
+	return results, true
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	results, ok := s.searchTerms(w, r, chi.URLParam(r, "term"))
+	if !ok {
 		return
 	}
 
-	if len(terms) == 0 {
+	if len(results) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	render.JSON(w, r, terms)
+	render.JSON(w, r, results)
 }
 
 func (s *Server) term(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +166,7 @@ func (s *Server) term(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	term, err := s.db.GetTerm(id)
+	term, err := s.db.CachedTerm(id)
 	if err != nil {
 		if errors.Cause(err) == pgx.ErrNoRows {
 			w.WriteHeader(http.StatusNotFound)
@@ -49,3 +179,42 @@ func (s *Server) term(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, r, term)
 }
+
+// namedTerm is a term found by name, along with the alias that matched
+type namedTerm struct {
+	*db.Term
+	// MatchedAlias is empty if the term's name matched
+	MatchedAlias string `json:"matched_alias,omitempty"`
+}
+
+func (s *Server) termByName(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil || strings.TrimSpace(name) == "" {
+		writeStatus(w, r, http.StatusBadRequest, "name: invalid name")
+		return
+	}
+
+	m, err := s.termByNameOrID(name)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeStatus(w, r, http.StatusNotFound, "term not found")
+			return
+		}
+		log.Errorf("Error getting term %q: %v", name, err)
+		writeStatus(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	render.JSON(w, r, namedTerm{Term: m.Term, MatchedAlias: m.Alias})
+}
+
+// termByNameOrID gets a term by its ID, name, or alias.
+// The docs have always used /term/:id, so IDs work everywhere names do.
+func (s *Server) termByNameOrID(name string) (m db.NameMatch, err error) {
+	if numberRegex.MatchString(name) {
+		id, _ := strconv.Atoi(name)
+		m.Term, err = s.db.CachedTerm(id)
+		return m, err
+	}
+	return s.db.TermByName(name)
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/pronouns/examples"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 500
+)
+
+type apiVersion int
+
+var apiVersionKey = apiVersion(0)
+
+// v2Context marks requests as using v2, so errors use the v2 envelope
+func v2Context(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiVersionKey, 2)))
+	})
+}
+
+func isV2(r *http.Request) bool {
+	v, _ := r.Context().Value(apiVersionKey).(int)
+	return v == 2
+}
+
+// errorEnvelope is the body of every v2 error response
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	// Code is the HTTP status code
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// termPage is a page of terms
+type termPage struct {
+	Terms  []*db.Term `json:"terms"`
+	Total  int        `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// intParam parses an optional integer query parameter, writing an error if it's invalid
+func intParam(w http.ResponseWriter, r *http.Request, name string, def, min, max int) (int, bool) {
+	v := r.FormValue(name)
+	if v == "" {
+		return def, true
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil || i < min || (max > 0 && i > max) {
+		msg := name + ": must be a number of at least " + strconv.Itoa(min)
+		if max > 0 {
+			msg += " and at most " + strconv.Itoa(max)
+		}
+		writeError(w, r, http.StatusBadRequest, msg)
+		return 0, false
+	}
+	return i, true
+}
+
+func (s *Server) v2Terms(w http.ResponseWriter, r *http.Request) {
+	category, ok := intParam(w, r, "category", 0, 0, 0)
+	if !ok {
+		return
+	}
+	flags, ok := intParam(w, r, "flags", int(search.FlagListHidden), 0, 0)
+	if !ok {
+		return
+	}
+	limit, ok := intParam(w, r, "limit", defaultPageLimit, 1, maxPageLimit)
+	if !ok {
+		return
+	}
+	offset, ok := intParam(w, r, "offset", 0, 0, 0)
+	if !ok {
+		return
+	}
+
+	var tags []string
+	for _, t := range strings.Split(r.FormValue("tags"), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	terms, total, err := s.db.FilterTerms(db.TermFilter{
+		Category: category,
+		Tags:     tags,
+		Mask:     search.TermFlag(flags),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+	if terms == nil {
+		terms = []*db.Term{}
+	}
+
+	render.JSON(w, r, termPage{
+		Terms:  terms,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (s *Server) v2Term(w http.ResponseWriter, r *http.Request) {
+	t, err := s.db.CachedTerm(idParam(r))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeError(w, r, http.StatusNotFound, "term not found")
+			return
+		}
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, t)
+}
+
+func (s *Server) v2Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.FormValue("q"))
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, "q: can't be empty")
+		return
+	}
+
+	results, ok := s.searchTerms(w, r, query)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, results)
+}
+
+func (s *Server) v2Categories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.db.GetCategories()
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, categories)
+}
+
+func (s *Server) v2Tags(w http.ResponseWriter, r *http.Request) {
+	tags, err := s.db.Tags()
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, tags)
+}
+
+func (s *Server) v2Explanations(w http.ResponseWriter, r *http.Request) {
+	explanations, err := s.db.GetAllExplanations()
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, explanations)
+}
+
+func (s *Server) v2Pronouns(w http.ResponseWriter, r *http.Request) {
+	lang := r.FormValue("language")
+	if _, ok := db.PronounLanguageByCode(lang); !ok {
+		writeError(w, r, http.StatusBadRequest, "language: unknown language")
+		return
+	}
+
+	pronouns, err := s.db.LanguagePronouns(lang, db.AlphabeticPronounOrder)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, pronouns)
+}
+
+func (s *Server) v2RenderPronouns(w http.ResponseWriter, r *http.Request) {
+	input := strings.Trim(r.FormValue("pronouns"), "/ ")
+	if input == "" {
+		writeError(w, r, http.StatusBadRequest, "pronouns: can't be empty")
+		return
+	}
+
+	lang, ok := db.PronounLanguageByCode(r.FormValue("language"))
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "language: unknown language")
+		return
+	}
+
+	sets, mixed, err := s.db.ParsePronouns(lang.Code, input)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeError(w, r, http.StatusNotFound, "pronoun set not found")
+			return
+		}
+		if err == db.ErrTooManyForms {
+			writeError(w, r, http.StatusBadRequest, "pronouns: too many forms")
+			return
+		}
+		writeDBError(w, r, err)
+		return
+	}
+
+	if !mixed {
+		sets = sets[:1]
+	}
+
+	name := r.FormValue("name")
+
+	ex, err := examples.Render(sets, name)
+	if err != nil {
+		log.Errorf("Error rendering pronouns %q: %v", input, err)
+		writeError(w, r, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	render.JSON(w, r, renderedPronouns{
+		Sets:     sets,
+		Name:     name,
+		Examples: ex,
+	})
+}
+
+func (s *Server) v2Languages(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, db.PronounLanguages)
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, "not found")
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+}
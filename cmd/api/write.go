@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// writeError writes an error with a message.
+// v1 errors are an object with just the message, v2 errors use the error envelope.
+func writeError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	render.Status(r, status)
+	if isV2(r) {
+		render.JSON(w, r, errorEnvelope{Error: errorBody{Code: status, Message: msg}})
+		return
+	}
+	render.JSON(w, r, apiError{Error: msg})
+}
+
+// writeStatus writes an error status. v1 only gets the status code, for compatibility.
+func writeStatus(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	if isV2(r) {
+		writeError(w, r, status, msg)
+		return
+	}
+	w.WriteHeader(status)
+}
+
+// requireScope only allows requests with an API key that has the given scope
+func (s *Server) requireScope(scope db.APIScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromContext(r.Context())
+			if key == nil {
+				writeError(w, r, http.StatusUnauthorized, "this endpoint requires an API key")
+				return
+			}
+
+			if !key.Scope.Has(scope) || !key.CreatedBy.IsValid() {
+				writeError(w, r, http.StatusForbidden, fmt.Sprintf("this endpoint requires the %v scope", scope))
+				return
+			}
+
+			// the key's owner might have lost the role they had when the key was created
+			current, err := s.staff.Limit(key.CreatedBy, key.Scope)
+			if err != nil {
+				log.Errorf("Error checking roles for %v: %v", key.CreatedBy, err)
+				writeError(w, r, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			if !current.Has(scope) {
+				writeError(w, r, http.StatusForbidden, fmt.Sprintf("this endpoint requires the %v scope, which the key's owner no longer has", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sendLog adds an audit log entry for a change made through the API, attributed to the key's owner
+func (s *Server) sendLog(r *http.Request, id int, subject auditlog.EntrySubject, action auditlog.ActionType, before, after interface{}) {
+	key := apiKeyFromContext(r.Context())
+	reason := fmt.Sprintf("Using API key %v (ID %v)", key.Name, key.ID)
+
+	s.auditLog.LogChange(id, subject, action, before, after, key.CreatedBy, &reason)
+}
+
+// decodeBody decodes a JSON request body, writing an error if it's invalid
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := render.DecodeJSON(r.Body, v)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+// writeDBError writes an appropriate error for a database error
+func writeDBError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		pgErr *pgconn.PgError
+		vErr  *db.ValidationError
+	)
+
+	switch {
+	case errors.As(err, &vErr):
+		writeError(w, r, http.StatusBadRequest, vErr.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		writeError(w, r, http.StatusConflict, "already exists")
+	case errors.Cause(err) == db.ErrorNoRowsAffected:
+		writeError(w, r, http.StatusNotFound, "not found")
+	default:
+		log.Errorf("Error in %v %v: %v", r.Method, r.URL.Path, err)
+		writeError(w, r, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func idParam(r *http.Request) int {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	return id
+}
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/db"
+)
+
+type nameRequest struct {
+	Name string `json:"name"`
+}
+
+func (req *nameRequest) valid(w http.ResponseWriter, r *http.Request) bool {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeError(w, r, http.StatusBadRequest, "name: can't be empty")
+		return false
+	}
+	if len(req.Name) > 200 {
+		writeError(w, r, http.StatusBadRequest, "name: too long")
+		return false
+	}
+	return true
+}
+
+func (s *Server) createCategory(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if !decodeBody(w, r, &req) || !req.valid(w, r) {
+		return
+	}
+
+	if _, err := s.db.CategoryID(req.Name); err == nil {
+		writeError(w, r, http.StatusConflict, "a category with that name already exists")
+		return
+	}
+
+	id, err := s.db.AddCategory(req.Name)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, db.Category{ID: id, Name: req.Name})
+}
+
+func (s *Server) updateCategory(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if !decodeBody(w, r, &req) || !req.valid(w, r) {
+		return
+	}
+
+	id := idParam(r)
+	err := s.db.RenameCategory(id, req.Name)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, db.Category{ID: id, Name: req.Name})
+}
+
+func (s *Server) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	err := s.db.RemoveCategory(idParam(r))
+	if err != nil {
+		if err == db.ErrCategoryNotEmpty {
+			writeError(w, r, http.StatusConflict, "category still has terms")
+			return
+		}
+		writeDBError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createTag(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if !decodeBody(w, r, &req) || !req.valid(w, r) {
+		return
+	}
+
+	err := s.db.AddTag(req.Name)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, req)
+}
+
+func (s *Server) updateTag(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if !decodeBody(w, r, &req) || !req.valid(w, r) {
+		return
+	}
+
+	err := s.db.RenameTag(chi.URLParam(r, "tag"), req.Name)
+	if err != nil {
+		if err == db.ErrTagExists {
+			writeError(w, r, http.StatusConflict, "a tag with that name already exists")
+			return
+		}
+		writeDBError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, req)
+}
+
+func (s *Server) deleteTag(w http.ResponseWriter, r *http.Request) {
+	err := s.db.RemoveTag(chi.URLParam(r, "tag"))
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type explanationRequest struct {
+	Name        *string   `json:"name"`
+	Aliases     *[]string `json:"aliases"`
+	Description *string   `json:"description"`
+}
+
+func (req explanationRequest) apply(w http.ResponseWriter, r *http.Request, e *db.Explanation) bool {
+	if req.Name != nil {
+		e.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Aliases != nil {
+		e.Aliases = *req.Aliases
+	}
+	if req.Description != nil {
+		e.Description = *req.Description
+	}
+
+	if e.Name == "" {
+		writeError(w, r, http.StatusBadRequest, "name: can't be empty")
+		return false
+	}
+	if strings.TrimSpace(e.Description) == "" {
+		writeError(w, r, http.StatusBadRequest, "description: can't be empty")
+		return false
+	}
+	return true
+}
+
+func (s *Server) createExplanation(w http.ResponseWriter, r *http.Request) {
+	var req explanationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	e := &db.Explanation{Aliases: []string{}}
+	if !req.apply(w, r, e) {
+		return
+	}
+
+	e, err := s.db.AddExplanation(e)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, e.ID, auditlog.ExplanationEntry, auditlog.CreateAction, nil, e)
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, e)
+}
+
+func (s *Server) updateExplanation(w http.ResponseWriter, r *http.Request) {
+	before, ok := s.explanationByID(w, r)
+	if !ok {
+		return
+	}
+
+	var req explanationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	e := *before
+	if !req.apply(w, r, &e) {
+		return
+	}
+
+	err := s.db.UpdateExplanation(&e)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, e.ID, auditlog.ExplanationEntry, auditlog.UpdateAction, before, e)
+
+	render.JSON(w, r, e)
+}
+
+func (s *Server) deleteExplanation(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.explanationByID(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.db.RemoveExplanation(e.ID)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, e.ID, auditlog.ExplanationEntry, auditlog.DeleteAction, e, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) explanationByID(w http.ResponseWriter, r *http.Request) (*db.Explanation, bool) {
+	e, err := s.db.ExplanationByID(idParam(r))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeError(w, r, http.StatusNotFound, "explanation not found")
+			return nil, false
+		}
+		writeDBError(w, r, err)
+		return nil, false
+	}
+	return e, true
+}
+
+type pronounRequest struct {
+	Language string   `json:"language"`
+	Forms    []string `json:"forms"`
+}
+
+func (req pronounRequest) set(w http.ResponseWriter, r *http.Request) (db.PronounSet, bool) {
+	set, err := db.NewPronounSet(req.Language, req.Forms)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "forms: "+err.Error())
+		return set, false
+	}
+	return set, true
+}
+
+func (s *Server) createPronouns(w http.ResponseWriter, r *http.Request) {
+	var req pronounRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	set, ok := req.set(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := s.db.AddPronoun(set)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	p, err := s.db.PronounSetByID(id)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, p.ID, auditlog.PronounsEntry, auditlog.CreateAction, nil, p)
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, p)
+}
+
+func (s *Server) updatePronouns(w http.ResponseWriter, r *http.Request) {
+	before, ok := s.pronounsByID(w, r)
+	if !ok {
+		return
+	}
+
+	req := pronounRequest{Language: before.Lang()}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	set, ok := req.set(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.db.UpdatePronoun(before.ID, set)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	p, err := s.db.PronounSetByID(before.ID)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, p.ID, auditlog.PronounsEntry, auditlog.UpdateAction, before, p)
+
+	render.JSON(w, r, p)
+}
+
+func (s *Server) deletePronouns(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.pronounsByID(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.db.RemovePronoun(p.ID)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, p.ID, auditlog.PronounsEntry, auditlog.DeleteAction, p, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) pronounsByID(w http.ResponseWriter, r *http.Request) (*db.PronounSet, bool) {
+	p, err := s.db.PronounSetByID(idParam(r))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeError(w, r, http.StatusNotFound, "pronoun set not found")
+			return nil, false
+		}
+		writeDBError(w, r, err)
+		return nil, false
+	}
+	return p, true
+}
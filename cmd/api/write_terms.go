@@ -0,0 +1,183 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+)
+
+// termRequest is the body for creating and updating terms. Fields that aren't set aren't changed.
+type termRequest struct {
+	Name            *string          `json:"name"`
+	Category        *int             `json:"category_id"`
+	Aliases         *[]string        `json:"aliases"`
+	Description     *string          `json:"description"`
+	Note            *string          `json:"note"`
+	Source          *string          `json:"source"`
+	Tags            *[]string        `json:"tags"`
+	ContentWarnings *string          `json:"content_warnings"`
+	ImageURL        *string          `json:"image_url"`
+	Flags           *search.TermFlag `json:"flags"`
+}
+
+// apply applies the request to t
+func (req termRequest) apply(t *db.Term) {
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Category != nil {
+		t.Category = *req.Category
+	}
+	if req.Aliases != nil {
+		t.Aliases = *req.Aliases
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+	if req.Source != nil {
+		t.Source = *req.Source
+	}
+	if req.Tags != nil {
+		t.DisplayTags = nil
+		t.Tags = nil
+		for _, tag := range *req.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			t.DisplayTags = append(t.DisplayTags, tag)
+			t.Tags = append(t.Tags, strings.ToLower(tag))
+		}
+	}
+	if req.Note != nil {
+		t.Note = *req.Note
+	}
+	if req.ContentWarnings != nil {
+		t.ContentWarnings = *req.ContentWarnings
+	}
+	if req.ImageURL != nil {
+		t.ImageURL = *req.ImageURL
+	}
+	if req.Flags != nil {
+		t.Flags = *req.Flags
+	}
+}
+
+// checkTerm validates the term and its category, and adds its tags
+func (s *Server) checkTerm(w http.ResponseWriter, r *http.Request, t *db.Term) bool {
+	err := db.ValidateTerm(t)
+	if err != nil {
+		writeDBError(w, r, err)
+		return false
+	}
+
+	c := s.db.CategoryFromID(t.Category)
+	if c.ID == 0 {
+		writeError(w, r, http.StatusBadRequest, "category_id: category doesn't exist")
+		return false
+	}
+	t.CategoryName = c.Name
+
+	for _, tag := range t.DisplayTags {
+		err = s.db.AddTag(tag)
+		if err != nil {
+			writeDBError(w, r, err)
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) createTerm(w http.ResponseWriter, r *http.Request) {
+	var req termRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	t := &db.Term{Source: "Unknown"}
+	req.apply(t)
+	if !s.checkTerm(w, r, t) {
+		return
+	}
+
+	t, err := s.db.AddTerm(t)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	t, err = s.db.GetTerm(t.ID)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, t.ID, auditlog.TermEntry, auditlog.CreateAction, nil, t)
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, t)
+}
+
+func (s *Server) updateTerm(w http.ResponseWriter, r *http.Request) {
+	before, ok := s.termByID(w, r)
+	if !ok {
+		return
+	}
+
+	var req termRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	t := *before
+	req.apply(&t)
+	if !s.checkTerm(w, r, &t) {
+		return
+	}
+
+	err := s.db.UpdateTerm(&t)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, t.ID, auditlog.TermEntry, auditlog.UpdateAction, before, t)
+
+	render.JSON(w, r, t)
+}
+
+func (s *Server) deleteTerm(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.termByID(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.db.RemoveTerm(t.ID)
+	if err != nil {
+		writeDBError(w, r, err)
+		return
+	}
+
+	s.sendLog(r, t.ID, auditlog.TermEntry, auditlog.DeleteAction, t, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) termByID(w http.ResponseWriter, r *http.Request) (*db.Term, bool) {
+	t, err := s.db.GetTerm(idParam(r))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			writeError(w, r, http.StatusNotFound, "term not found")
+			return nil, false
+		}
+		writeDBError(w, r, err)
+		return nil, false
+	}
+	return t, true
+}
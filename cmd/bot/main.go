@@ -28,7 +28,6 @@ import (
 	"github.com/termora/berry/common"
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
-	dbsearch "github.com/termora/berry/db/search"
 	"github.com/termora/berry/db/search/typesense"
 )
 
@@ -104,17 +103,12 @@ func run(ctx *cli.Context) error {
 		}
 	}
 
-	// sync terms
-	terms, err := d.GetTerms(dbsearch.FlagSearchHidden)
-	if err != nil {
-		log.Fatalf("Couldn't fetch all terms: %v", err)
-	}
-
-	err = d.SyncTerms(terms)
-	if err != nil {
-		log.Fatalf("Couldn't synchronize terms: %v", err)
+	// the bot is the only process that syncs the search index, so every change is synced exactly once,
+	// no matter which process made it. all terms are synced when the listener connects.
+	if c.Core.TypesenseURL != "" && c.Core.TypesenseKey != "" {
+		d.OnChange(d.SyncSearch)
 	}
-	log.Info("Synchronized terms with search instance!")
+	d.Listen(ctx.Context)
 
 	log.Info("Connected to database.")
 
@@ -179,6 +173,9 @@ func run(ctx *cli.Context) error {
 			log.Info("Syncing slash commands...")
 		}
 		err = bot.Router.SyncCommands(c.Bot.SlashGuilds...)
+		if err == nil {
+			err = bot.SyncUserCommands(c.Bot.SlashGuilds...)
+		}
 		if err != nil {
 			log.Errorf("Couldn't sync commands: %v", err)
 		} else {
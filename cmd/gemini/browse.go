@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~adnano/go-gemini"
+	"github.com/termora/berry/db"
+)
+
+// recentCount is the number of terms shown in both lists on the recent page
+const recentCount = 25
+
+// pagePath splits a path like "a/2" into its first segment and page number.
+// ok is false if the page number isn't valid.
+func pagePath(path string) (first string, page int, ok bool) {
+	path = strings.Trim(path, "/")
+	first = path
+	page = 1
+
+	if i := strings.Index(path, "/"); i != -1 {
+		first = path[:i]
+
+		var err error
+		page, err = strconv.Atoi(path[i+1:])
+		if err != nil || page < 1 {
+			return first, 0, false
+		}
+	}
+	return first, page, true
+}
+
+func (s *site) browse(ctx context.Context, w gemini.ResponseWriter, r *gemini.Request) {
+	slug, page, ok := pagePath(r.URL.Path)
+	if !ok {
+		w.WriteHeader(gemini.StatusNotFound, "Page not found")
+		return
+	}
+
+	data := &renderData{
+		Conf:    s.conf,
+		Letters: db.BrowseLetters,
+	}
+
+	// the index only lists letters and categories, all terms on one page would be too long
+	if slug == "" {
+		cats, err := s.db.CategoryCounts()
+		if err != nil {
+			s.sugar.Errorf("error fetching categories: %v", err)
+			w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+			return
+		}
+		data.Categories = cats
+
+		s.writePage(w, "browse", data)
+		return
+	}
+
+	data.Letter, ok = db.LetterFromSlug(slug)
+	if !ok {
+		w.WriteHeader(gemini.StatusNotFound, "Page not found")
+		return
+	}
+
+	terms, err := s.db.LetterTerms(data.Letter)
+	if err != nil {
+		s.sugar.Errorf("error fetching terms: %v", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
+	}
+
+	data.Terms, data.Pages = db.Paginate(terms, page, db.BrowsePageSize)
+	if page > data.Pages {
+		w.WriteHeader(gemini.StatusNotFound, "Page not found")
+		return
+	}
+	data.Page = page
+	data.PageURL = "/browse/" + db.LetterSlug(data.Letter) + "/"
+
+	s.writePage(w, "browse-letter", data)
+}
+
+func (s *site) category(ctx context.Context, w gemini.ResponseWriter, r *gemini.Request) {
+	idStr, page, ok := pagePath(r.URL.Path)
+	if !ok {
+		w.WriteHeader(gemini.StatusNotFound, "Page not found")
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		w.WriteHeader(gemini.StatusNotFound, "Category not found")
+		return
+	}
+
+	cat := s.db.CategoryFromID(id)
+	if cat.ID == 0 {
+		w.WriteHeader(gemini.StatusNotFound, "Category not found")
+		return
+	}
+
+	terms, err := s.db.CategoryTerms(id)
+	if err != nil {
+		s.sugar.Errorf("error fetching terms: %v", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
+	}
+
+	data := &renderData{
+		Conf:     s.conf,
+		Category: cat,
+		Page:     page,
+		PageURL:  "/category/" + strconv.Itoa(cat.ID) + "/",
+	}
+
+	data.Terms, data.Pages = db.Paginate(terms, page, db.BrowsePageSize)
+	if page > data.Pages {
+		w.WriteHeader(gemini.StatusNotFound, "Page not found")
+		return
+	}
+
+	s.writePage(w, "category", data)
+}
+
+func (s *site) recent(ctx context.Context, w gemini.ResponseWriter, r *gemini.Request) {
+	added, updated, err := s.db.RecentTerms(recentCount)
+	if err != nil {
+		s.sugar.Errorf("error fetching terms: %v", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
+	}
+
+	s.writePage(w, "recent", &renderData{
+		Conf:    s.conf,
+		Terms:   added,
+		Updated: updated,
+	})
+}
+
+// writePage renders a template and writes it as the response
+func (s *site) writePage(w gemini.ResponseWriter, name string, data *renderData) {
+	page, err := s.Render(name, data)
+	if err != nil {
+		s.sugar.Errorf("error rendering %v: %v", name, err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Something went wrong")
+		return
+	}
+
+	w.SetMediaType(mimeType)
+	_, err = io.WriteString(w, page)
+	if err != nil {
+		s.sugar.Error("error uploading", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"git.sr.ht/~adnano/go-gemini"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+func (s *site) explanation(ctx context.Context, w gemini.ResponseWriter, r *gemini.Request) {
+	name, err := url.PathUnescape(strings.Trim(r.URL.Path, "/"))
+	if err != nil {
+		s.sugar.Errorf("error decoding url: %v, %v", err, r.Conn().RemoteAddr())
+		w.WriteHeader(gemini.StatusBadRequest, "Invalid Input")
+		return
+	}
+
+	if name == "" {
+		ex, err := s.db.GetAllExplanations()
+		if err != nil {
+			s.sugar.Errorf("error fetching explanations: %v", err)
+			w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+			return
+		}
+
+		sort.Slice(ex, func(i, j int) bool {
+			return strings.ToLower(ex[i].Name) < strings.ToLower(ex[j].Name)
+		})
+
+		s.writePage(w, "explanations", &renderData{
+			Conf:         s.conf,
+			Explanations: ex,
+		})
+		return
+	}
+
+	e, err := s.db.ExplanationByName(name)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			w.WriteHeader(gemini.StatusNotFound, "Explanation not found")
+			return
+		}
+		s.sugar.Errorf("error fetching explanation: %v", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
+	}
+
+	var links []linkPair
+	e.Description, links = linkReformatter(s.db.LinkTerms(e.Description))
+
+	s.writePage(w, "explanation", &renderData{
+		Conf:        s.conf,
+		Explanation: e,
+		Links:       links,
+	})
+}
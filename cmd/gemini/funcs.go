@@ -6,6 +6,8 @@ import (
 	"strings"
 	"text/template"
 	"time"
+
+	"github.com/termora/berry/db"
 )
 
 // HeadlineLen is the length of a headline (used in embeds)
@@ -34,6 +36,9 @@ func funcMap() template.FuncMap {
 		"isPlural": func(i int) bool { return i != 1 },
 		"title":    strings.Title,
 
+		"letterSlug": db.LetterSlug,
+		"fieldName":  db.FieldName,
+
 		"quoteMultiline": func(s string) string {
 			if strings.Count(s, "\n") < 1 {
 				return s
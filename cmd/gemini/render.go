@@ -26,7 +26,26 @@ type renderData struct {
 	Term  *db.Term
 	Terms []*db.Term
 
+	Category   *db.Category
+	Categories []db.CategoryCount
+	Letter     string
+	Letters    []string
+	// Updated is the list of recently updated terms, Terms is recently added terms on the same page
+	Updated []*db.Term
+
+	// Page and Pages are set for paginated lists, PageURL is the URL that the page number is appended to
+	Page    int
+	Pages   int
+	PageURL string
+
 	TermLinks TermLinks
+	// Revisions are a term's public changes, newest first
+	Revisions []db.Revision
+
+	Explanation  *db.Explanation
+	Explanations []*db.Explanation
+	// Links are the links in an explanation's text, in order
+	Links []linkPair
 
 	Query string
 	MD    string
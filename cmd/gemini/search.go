@@ -3,10 +3,15 @@ package main
 import (
 	"context"
 	"io"
+	"net/url"
+	"strings"
 
 	"git.sr.ht/~adnano/go-gemini"
+	"github.com/termora/berry/db"
 )
 
+// search searches for terms. Filters are given in the path as query parameters, as the query itself is the search input,
+// for example /search/category=1&no_cw=true?query
 func (s *site) search(ctx context.Context, w gemini.ResponseWriter, r *gemini.Request) {
 	q, err := gemini.QueryUnescape(r.URL.RawQuery)
 	if err != nil {
@@ -20,8 +25,23 @@ func (s *site) search(ctx context.Context, w gemini.ResponseWriter, r *gemini.Re
 		return
 	}
 
-	//	q := template.HTML(bluemonday.UGCPolicy().Sanitize(c.QueryParam("q")))
-	terms, err := s.db.Search(q, 0, []string{})
+	v, err := url.ParseQuery(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), "/search"), "/"))
+	if err != nil {
+		w.WriteHeader(gemini.StatusBadRequest, "Invalid search filter")
+		return
+	}
+	filter, err := s.db.ParseSearchFilter(v)
+	if err != nil {
+		w.WriteHeader(gemini.StatusBadRequest, "Invalid search filter: "+err.Error())
+		return
+	}
+
+	categories, err := s.db.CategoryCounts()
+	if err != nil {
+		s.sugar.Errorf("error fetching categories: %v", err)
+	}
+
+	terms, err := s.db.FilteredSearch(q, filter)
 
 	var page string
 	if err != nil || len(terms) == 0 {
@@ -29,6 +49,7 @@ func (s *site) search(ctx context.Context, w gemini.ResponseWriter, r *gemini.Re
 			Conf:  s.conf,
 			Path:  r.URL.Path,
 			Query: q,
+			Links: filterLinks(q, filter, categories, nil),
 		})
 	} else {
 		page, err = s.Render("search-results", &renderData{
@@ -36,6 +57,7 @@ func (s *site) search(ctx context.Context, w gemini.ResponseWriter, r *gemini.Re
 			Terms: terms,
 			Path:  r.URL.Path,
 			Query: q,
+			Links: filterLinks(q, filter, categories, terms),
 		})
 	}
 
@@ -51,3 +73,75 @@ func (s *site) search(ctx context.Context, w gemini.ResponseWriter, r *gemini.Re
 		s.sugar.Error("error uploading", err)
 	}
 }
+
+// searchURL returns the URL for a search with the given filter
+func searchURL(q string, f db.SearchFilter) string {
+	return "/search/" + f.Values().Encode() + "?" + gemini.QueryEscape(q)
+}
+
+// maxExcludeLinks is the maximum number of tags that can be excluded from the results page
+const maxExcludeLinks = 10
+
+// filterLinks returns links to change the search's filters, as gemini doesn't have forms
+func filterLinks(q string, f db.SearchFilter, categories []db.CategoryCount, terms []*db.Term) (links []linkPair) {
+	add := func(name string, f db.SearchFilter) {
+		links = append(links, linkPair{Name: name, Dest: searchURL(q, f)})
+	}
+
+	if len(f.Values()) > 0 {
+		add("Clear all filters", db.SearchFilter{})
+	}
+
+	if f.Category != 0 {
+		c := f
+		c.Category = 0
+		add("Search in all categories", c)
+	}
+	for _, cat := range categories {
+		if cat.ID != f.Category {
+			c := f
+			c.Category = cat.ID
+			add("Only search "+cat.Name+" terms", c)
+		}
+	}
+
+	c := f
+	c.NoCW = !f.NoCW
+	if c.NoCW {
+		add("Hide terms with content warnings", c)
+	} else {
+		add("Show terms with content warnings", c)
+	}
+
+	for _, sort := range db.SearchSorts {
+		if sort != f.Sort {
+			c := f
+			c.Sort = sort
+			add("Sort by "+string(sort), c)
+		}
+	}
+
+	for i, tag := range f.Ignore {
+		c := f
+		c.Ignore = append(append([]string{}, f.Ignore[:i]...), f.Ignore[i+1:]...)
+		add("Include terms tagged "+tag+" again", c)
+	}
+
+	seen := map[string]bool{}
+	for _, t := range terms {
+		for _, tag := range t.Tags {
+			if len(seen) >= maxExcludeLinks {
+				break
+			}
+			if seen[tag] || f.Ignores(tag) {
+				continue
+			}
+			seen[tag] = true
+
+			c := f
+			c.Ignore = append(append([]string{}, f.Ignore...), tag)
+			add("Exclude terms tagged "+tag, c)
+		}
+	}
+	return links
+}
@@ -66,7 +66,8 @@ func (s *site) Run() {
 		s.sugar.Fatal("Template Error:", err)
 	}
 
-	s.db, err = db.Init(s.conf.DatabaseURL, s.sugar)
+	db.Debug = s.sugar.Debugf
+	s.db, err = db.Init(s.conf.DatabaseURL)
 	if err != nil {
 		s.sugar.Fatalf("Error connecting to database: %v", err)
 	}
@@ -79,15 +80,22 @@ func (s *site) Run() {
 		s.sugar.Errorf("Error updating feeds: %v", err)
 	}
 
-	// Typesense requires a bot running to sync terms
+	// Typesense is fully synced when the bot starts, after that every process syncs changes as they happen
 	if s.conf.Typesense.URL != "" && s.conf.Typesense.Key != "" {
-		s.db.Searcher, err = typesense.New(s.conf.Typesense.URL, s.conf.Typesense.Key, s.db.Pool, s.sugar.Debugf)
+		s.db.Searcher, err = typesense.New(s.conf.Typesense.URL, s.conf.Typesense.Key, s.db.Pool)
 		if err != nil {
 			s.sugar.Fatalf("Couldn't connect to Typesense: %v", err)
 		}
 		s.sugar.Info("Connected to Typesense server")
 	}
 
+	s.db.OnChange(func(n db.Notification) {
+		if n.Table == db.TermsTable || n.Table == db.AuditLogTable || n.Action == db.ReconnectAction {
+			s.feeds.Invalidate()
+		}
+	})
+	s.db.Listen(ctx)
+
 	s.mux = &gemini.Mux{}
 	s.gemini = &gemini.Server{
 		Handler:        gemini.TimeoutHandler(s.mux, time.Second*15, "Server Action Timeout"), // chi's .Use is nicer for middlewares smh
@@ -99,6 +107,10 @@ func (s *site) Run() {
 	s.mux.Handle("/tag/", gemini.StripPrefix("/tag/", gemini.HandlerFunc(s.tag)))
 	s.mux.Handle("/term/", gemini.StripPrefix("/term/", gemini.HandlerFunc(s.term)))
 	s.mux.HandleFunc("/search/", s.search)
+	s.mux.Handle("/browse/", gemini.StripPrefix("/browse/", gemini.HandlerFunc(s.browse)))
+	s.mux.Handle("/category/", gemini.StripPrefix("/category/", gemini.HandlerFunc(s.category)))
+	s.mux.HandleFunc("/recent", s.recent)
+	s.mux.Handle("/explanations/", gemini.StripPrefix("/explanations/", gemini.HandlerFunc(s.explanation)))
 	s.mux.Handle("/about/", gemini.StripPrefix("/about/", gemini.HandlerFunc(s.staticPage)))
 	s.mux.Handle("/file/", gemini.StripPrefix("/file/", gemini.HandlerFunc(s.file)))
 	// not currently used, can be uncommented if needed
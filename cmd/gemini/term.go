@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"git.sr.ht/~adnano/go-gemini"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
 	"github.com/termora/berry/db"
 )
 
@@ -18,6 +20,8 @@ func (s *site) term(ctx context.Context, w gemini.ResponseWriter, r *gemini.Requ
 	var t *db.Term
 
 	name := r.URL.Path
+	history := strings.HasSuffix(name, "/history")
+	name = strings.TrimSuffix(name, "/history")
 	name, err := url.PathUnescape(name)
 	if err != nil {
 		s.sugar.Errorf("error decoding url: %v, %v", err, r.Conn().RemoteAddr())
@@ -28,30 +32,24 @@ func (s *site) term(ctx context.Context, w gemini.ResponseWriter, r *gemini.Requ
 	if numberRegex.MatchString(name) {
 		id, _ := strconv.Atoi(name)
 
-		t, err = s.db.GetTerm(id)
-		if err != nil {
-			s.sugar.Errorf("error fetching tag content: %v", err)
-			w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
-			return
-		}
+		t, err = s.db.CachedTerm(id)
 	} else {
-		terms, err := s.db.GetTerms(0)
-		if err != nil {
-			s.sugar.Errorf("error fetching tag content: %v", err)
-			w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		var m db.NameMatch
+		m, err = s.db.TermByName(name)
+		t = m.Term
+	}
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			w.WriteHeader(gemini.StatusNotFound, "Term not found")
 			return
 		}
-
-		for _, i := range terms {
-			if strings.EqualFold(i.Name, name) {
-				t = i
-				break
-			}
-		}
+		s.sugar.Errorf("error fetching term: %v", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
 	}
 
-	if t == nil {
-		w.WriteHeader(gemini.StatusNotFound, "Term not found")
+	if history {
+		s.termHistory(w, t)
 		return
 	}
 
@@ -91,6 +89,32 @@ func (s *site) term(ctx context.Context, w gemini.ResponseWriter, r *gemini.Requ
 	}
 }
 
+func (s *site) termHistory(w gemini.ResponseWriter, t *db.Term) {
+	revs, err := s.db.TermHistory(t.ID)
+	if err != nil {
+		s.sugar.Errorf("error fetching history for term %v: %v", t.ID, err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Database Error")
+		return
+	}
+
+	page, err := s.Render("history", &renderData{
+		Conf:      s.conf,
+		Term:      t,
+		Revisions: revs,
+	})
+	if err != nil {
+		s.sugar.Error("error rendering history: ", err)
+		w.WriteHeader(gemini.StatusTemporaryFailure, "Something went wrong")
+		return
+	}
+
+	w.SetMediaType(mimeType)
+	_, err = io.WriteString(w, page)
+	if err != nil {
+		s.sugar.Error("error uploading", err)
+	}
+}
+
 var linkRegex = regexp.MustCompile(`\[([^\]\[\n]+)\]\((\/term\/(?:\d+))\)|https?:\/\/(\S+)`)
 
 type linkPair struct {
@@ -0,0 +1,106 @@
+package site
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+)
+
+// recentCount is the number of terms shown in both lists on the recent page
+const recentCount = 25
+
+// categoryNav adds the categories shown in the navigation to the context, for renderData.parse
+func (s *site) categoryNav(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cats, err := s.db.CategoryCounts()
+		if err != nil {
+			// the page is still usable without navigation
+			log.Errorf("Error getting categories: %v", err)
+		} else {
+			c.Set("categories", cats)
+		}
+		c.Set("static", s.static)
+		return next(c)
+	}
+}
+
+// pageParam returns the page query parameter, or 1 if it isn't set.
+// ok is false if it's set but isn't a valid page number.
+func pageParam(c echo.Context) (page int, ok bool) {
+	if c.QueryParam("page") == "" {
+		return 1, true
+	}
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	return page, err == nil && page >= 1
+}
+
+func (s *site) browse(c echo.Context) (err error) {
+	notFound := func() error {
+		return c.Render(http.StatusNotFound, "404.html", (&renderData{
+			Conf: s.Config,
+		}).parse(c))
+	}
+
+	page, ok := pageParam(c)
+	if !ok {
+		return notFound()
+	}
+
+	var (
+		letter string
+		terms  []*db.Term
+		path   = "/browse"
+	)
+	if c.Param("letter") != "" {
+		letter, ok = db.LetterFromSlug(c.Param("letter"))
+		if !ok {
+			return notFound()
+		}
+		path += "/" + db.LetterSlug(letter)
+
+		terms, err = s.db.LetterTerms(letter)
+	} else {
+		terms, err = s.db.CachedTerms(search.FlagListHidden)
+	}
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	terms, pages := db.Paginate(terms, page, s.pageSize)
+	if page > pages {
+		return notFound()
+	}
+
+	canonical := s.Config.BaseURL + path
+	if page > 1 {
+		canonical += "?page=" + strconv.Itoa(page)
+	}
+
+	return c.Render(http.StatusOK, "browse.html", (&renderData{
+		Conf:      s.Config,
+		Letter:    letter,
+		Terms:     terms,
+		Page:      page,
+		Pages:     pages,
+		PageURL:   path + "?page=",
+		Canonical: canonical,
+	}).parse(c))
+}
+
+func (s *site) recent(c echo.Context) (err error) {
+	added, updated, err := s.db.RecentTerms(recentCount)
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Render(http.StatusOK, "recent.html", (&renderData{
+		Conf:      s.Config,
+		Terms:     added,
+		Updated:   updated,
+		Canonical: s.Config.BaseURL + "/recent",
+	}).parse(c))
+}
@@ -0,0 +1,283 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+	"github.com/urfave/cli/v2"
+)
+
+var buildCommand = &cli.Command{
+	Name:   "build",
+	Usage:  "Render the website to static files",
+	Action: build,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "out",
+			Aliases: []string{"o"},
+			Value:   "public",
+			Usage:   "Output directory",
+		},
+	},
+}
+
+// builder renders pages by requesting them from the site's router, so static pages are the same as the live ones
+type builder struct {
+	e   *echo.Echo
+	out string
+
+	pages int
+}
+
+// build renders every page of the site into a directory that can be served by any static file host.
+// Pages are written as path/index.html, and everything that needs the server
+// (search, pagination, dark mode preferences) is replaced or left out.
+func build(ctx *cli.Context) error {
+	c := common.ReadConfig()
+
+	d, err := db.Init(c.Core.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	d.TermBaseURL = "/term/"
+	log.Info("Connected to database.")
+
+	s := newSite(d, c.Site)
+	s.static = true
+	// query parameters don't work on static hosts, so lists aren't paginated
+	s.pageSize = math.MaxInt32
+
+	e := echo.New()
+	e.Renderer = newRenderer()
+	s.routes(e)
+
+	out, err := filepath.Abs(ctx.String("out"))
+	if err != nil {
+		return err
+	}
+	b := &builder{e: e, out: out}
+
+	start := time.Now()
+	err = s.buildPages(b)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Built %v pages into %v in %v", b.pages, out, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (s *site) buildPages(b *builder) error {
+	paths := []string{"/", "/browse", "/recent", "/search", "/explanations", "/pronouns",
+		"/sitemap.xml", "/robots.txt", "/feeds/feed.rss", "/feeds/feed.atom", "/feeds/feed.json", "/tag/untagged", "/embed.js"}
+
+	for _, l := range db.BrowseLetters {
+		paths = append(paths, "/browse/"+db.LetterSlug(l))
+	}
+
+	terms, err := s.db.CachedTerms(0)
+	if err != nil {
+		return err
+	}
+	for _, t := range terms {
+		// term links in descriptions and feeds use IDs, which redirect to the term's page
+		paths = append(paths, termURL(t), "/term/"+strconv.Itoa(t.ID), historyURL(t), embedURL(t, ""))
+	}
+
+	tags, err := s.db.TagSlugs()
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		paths = append(paths, "/tag/"+url.PathEscape(t.Slug))
+	}
+
+	categories, err := s.db.GetCategories()
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		paths = append(paths, "/category/"+strconv.Itoa(c.ID))
+	}
+
+	explanations, err := s.db.GetAllExplanations()
+	if err != nil {
+		return err
+	}
+	for _, e := range explanations {
+		paths = append(paths, explanationURL(e.Name))
+	}
+
+	pronouns, err := s.db.Pronouns(db.AlphabeticPronounOrder)
+	if err != nil {
+		return err
+	}
+	for _, p := range pronouns {
+		paths = append(paths, pronounURL(p))
+	}
+
+	about, err := fs.ReadDir(staticPages, "static/pages")
+	if err != nil {
+		return err
+	}
+	for _, f := range about {
+		if strings.HasSuffix(f.Name(), ".md") {
+			paths = append(paths, "/about/"+strings.TrimSuffix(f.Name(), ".md"))
+		}
+	}
+
+	files, err := s.db.Files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		paths = append(paths, "/file/"+f.ID.String()+"/"+url.PathEscape(f.Filename))
+	}
+
+	// one broken page shouldn't stop the rest from being built, but the build still fails
+	var failed int
+	for _, p := range paths {
+		if err := b.page(p, http.StatusOK); err != nil {
+			log.Error(err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%v pages couldn't be built", failed)
+	}
+
+	// most static hosts serve 404.html for missing pages
+	if err := b.page("/about/404", http.StatusNotFound); err != nil {
+		return err
+	}
+
+	if err := b.searchIndex(terms); err != nil {
+		return err
+	}
+	return b.assets()
+}
+
+// page requests a page and writes it to the output directory.
+// HTML pages are written as path/index.html, so they're served at the same URL as on the live site.
+func (b *builder) page(p string, status int) error {
+	req := httptest.NewRequest(http.MethodGet, p, nil)
+	rec := httptest.NewRecorder()
+	b.e.ServeHTTP(rec, req)
+
+	body := rec.Body.Bytes()
+	switch {
+	case rec.Code >= 300 && rec.Code < 400:
+		loc := html.EscapeString(rec.Header().Get("Location"))
+		body = []byte(fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="UTF-8"><meta http-equiv="refresh" content="0; url=%v"><link rel="canonical" href="%v"></head><body><a href="%v">Redirecting...</a></body></html>`, loc, loc, loc))
+	case rec.Code != status:
+		return fmt.Errorf("building %v: expected status %v, got %v", p, status, rec.Code)
+	}
+
+	name := req.URL.Path
+	if status == http.StatusNotFound {
+		name = "/404.html"
+	} else if strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || rec.Code >= 300 {
+		name = path.Join(name, "index.html")
+	}
+
+	b.pages++
+	return b.write(name, body)
+}
+
+// write writes a file in the output directory, creating directories as needed
+func (b *builder) write(name string, data []byte) error {
+	fn := filepath.Join(b.out, filepath.FromSlash(path.Clean("/"+name)))
+	if !strings.HasPrefix(fn, b.out+string(filepath.Separator)) {
+		return fmt.Errorf("building %v: path is outside the output directory", name)
+	}
+
+	err := os.MkdirAll(filepath.Dir(fn), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fn, data, 0o644)
+}
+
+// assets copies the site's static files
+func (b *builder) assets() error {
+	return fs.WalkDir(staticFS, "static", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasPrefix(p, "static/pages/") {
+			return err
+		}
+
+		data, err := staticFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return b.write(p, data)
+	})
+}
+
+// searchTerm is a term in the static site's search index
+type searchTerm struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	URL          string    `json:"url"`
+	Aliases      []string  `json:"aliases"`
+	Category     int       `json:"category"`
+	CategoryName string    `json:"category_name"`
+	Tags         []string  `json:"tags"`
+	CW           bool      `json:"cw"`
+	Description  string    `json:"description"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// searchIndex writes the index used by search.js for client-side search
+func (b *builder) searchIndex(terms []*db.Term) error {
+	index := make([]searchTerm, 0, len(terms))
+	for _, t := range terms {
+		if t.Flags&search.FlagSearchHidden != 0 {
+			continue
+		}
+
+		index = append(index, searchTerm{
+			ID:           t.ID,
+			Name:         t.Name,
+			URL:          termURL(t),
+			Aliases:      t.Aliases,
+			Category:     t.Category,
+			CategoryName: t.CategoryName,
+			Tags:         t.Tags,
+			CW:           t.ContentWarnings != "",
+			Description:  headlineLinkRegexp.ReplaceAllString(t.Description, "$1"),
+			Created:      t.Created,
+			LastModified: t.LastModified,
+		})
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return b.write("/search-index.json", data)
+}
+
+func (s *site) staticSearch(c echo.Context) (err error) {
+	return c.Render(http.StatusOK, "staticSearch.html", (&renderData{
+		Conf:  s.Config,
+		Title: "Search",
+	}).parse(c))
+}
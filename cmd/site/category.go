@@ -0,0 +1,58 @@
+package site
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/db"
+)
+
+func (s *site) category(c echo.Context) (err error) {
+	notFound := func() error {
+		return c.Render(http.StatusNotFound, "404.html", (&renderData{
+			Conf: s.Config,
+		}).parse(c))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return notFound()
+	}
+
+	page, ok := pageParam(c)
+	if !ok {
+		return notFound()
+	}
+
+	cat := s.db.CategoryFromID(id)
+	if cat.ID == 0 {
+		return notFound()
+	}
+
+	terms, err := s.db.CategoryTerms(id)
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	terms, pages := db.Paginate(terms, page, s.pageSize)
+	if page > pages {
+		return notFound()
+	}
+
+	path := "/category/" + strconv.Itoa(cat.ID)
+	canonical := s.Config.BaseURL + path
+	if page > 1 {
+		canonical += "?page=" + strconv.Itoa(page)
+	}
+
+	return c.Render(http.StatusOK, "cat.html", (&renderData{
+		Conf:      s.Config,
+		Category:  cat,
+		Terms:     terms,
+		Page:      page,
+		Pages:     pages,
+		PageURL:   path + "?page=",
+		Canonical: canonical,
+	}).parse(c))
+}
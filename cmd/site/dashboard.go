@@ -0,0 +1,305 @@
+package site
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// sessionCookie is the name of the cookie holding the dashboard session token
+const sessionCookie = "dashboard_session"
+
+// loginCSRFCookie holds the CSRF token for the login form, as there's no session to store it in yet.
+// Without it, another site could log someone in to the dashboard with the other site's owner's login link.
+const loginCSRFCookie = "dashboard_login_csrf"
+
+// dashboardRoutes adds the dashboard's routes to g, which is the /admin group.
+// Logging in is done with a one-time link from the bot's `admin dashboard` command, which is exchanged for a session cookie.
+func (s *site) dashboardRoutes(g *echo.Group) {
+	// uploaded files are limited to 1 MB, the rest is room for the other form values
+	g.Use(noStore, middleware.BodyLimit("2M"))
+
+	g.GET("/login", s.dashboardLogin)
+	g.POST("/login", s.dashboardUseLogin)
+
+	g.Use(s.requireSession)
+
+	g.GET("", s.dashboard)
+	g.POST("/logout", s.dashboardLogout)
+
+	g.GET("/terms", s.dashboardTerms)
+	g.GET("/terms/new", s.dashboardEditTerm)
+	g.POST("/terms/new", s.dashboardSaveTerm)
+	g.GET("/terms/:id", s.dashboardEditTerm)
+	g.POST("/terms/:id", s.dashboardSaveTerm)
+	g.POST("/terms/:id/delete", s.dashboardDeleteTerm, requireScope(db.ScopeAdmin))
+	g.POST("/preview", s.dashboardPreview)
+
+	g.GET("/submissions", s.dashboardSubmissions)
+	g.POST("/submissions/:id/:action", s.dashboardResolveSubmission)
+
+	g.GET("/log", s.dashboardLog)
+
+	g.GET("/files", s.dashboardFiles)
+	g.POST("/files", s.dashboardUpload)
+}
+
+// noStore stops browsers and proxies from caching dashboard pages
+func noStore(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "no-store")
+		return next(c)
+	}
+}
+
+// requireSession checks the session cookie, adding the session to the context for renderData.parse.
+// The session's scope is limited to the user's current roles, and it's ended if they aren't a director anymore.
+// All POST requests must also have the session's CSRF token in the "csrf" form value.
+func (s *site) requireSession(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cookie, err := c.Cookie(sessionCookie)
+		if err != nil || cookie.Value == "" {
+			return c.Redirect(http.StatusSeeOther, "/admin/login")
+		}
+
+		sess, err := s.db.DashboardSession(cookie.Value)
+		if err != nil {
+			if errors.Cause(err) != pgx.ErrNoRows {
+				log.Errorf("Error getting dashboard session: %v", err)
+				return c.NoContent(http.StatusInternalServerError)
+			}
+			return c.Redirect(http.StatusSeeOther, "/admin/login")
+		}
+
+		scope, err := s.staff.Limit(sess.UserID, sess.Scope)
+		if err != nil {
+			log.Errorf("Error checking roles for dashboard session: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		if !scope.Has(db.ScopeDirector) {
+			log.Infof("Ending dashboard session for %v (%v), as they aren't a director anymore", sess.Name, sess.UserID)
+			err = s.db.EndDashboardSession(cookie.Value)
+			if err != nil {
+				log.Errorf("Error ending dashboard session: %v", err)
+			}
+			return c.Redirect(http.StatusSeeOther, "/admin/login")
+		}
+		sess.Scope = scope
+
+		if c.Request().Method == http.MethodPost &&
+			subtle.ConstantTimeCompare([]byte(c.FormValue("csrf")), []byte(sess.CSRFToken)) != 1 {
+			return c.String(http.StatusForbidden, "Invalid or missing CSRF token, reload the page and try again.")
+		}
+
+		c.Set("session", &sess)
+		return next(c)
+	}
+}
+
+// requireScope only allows sessions with the given scope
+func requireScope(scope db.APIScope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if sess := session(c); sess == nil || !sess.Scope.Has(scope) {
+				return c.String(http.StatusForbidden, "You're not allowed to do that.")
+			}
+			return next(c)
+		}
+	}
+}
+
+// session returns the request's dashboard session, set by requireSession
+func session(c echo.Context) *db.DashboardSession {
+	sess, _ := c.Get("session").(*db.DashboardSession)
+	return sess
+}
+
+// dashboardLogin shows a button to log in with a login link's token.
+// The token isn't used until the form is submitted, so link previews in Discord don't use it up.
+func (s *site) dashboardLogin(c echo.Context) (err error) {
+	data := &renderData{
+		Conf:  s.Config,
+		Title: "Log in",
+	}
+
+	token := c.QueryParam("token")
+	if token != "" {
+		valid, err := s.db.DashboardLoginValid(token)
+		if err != nil {
+			log.Errorf("Error checking dashboard login: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		if !valid {
+			data.Error = "That login link is invalid, has expired, or was already used."
+			token = ""
+		}
+	}
+	data.LoginToken = token
+
+	if token != "" {
+		data.LoginCSRF, err = db.RandomToken()
+		if err != nil {
+			log.Errorf("Error generating login CSRF token: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+
+		c.SetCookie(&http.Cookie{
+			Name:     loginCSRFCookie,
+			Value:    data.LoginCSRF,
+			Path:     "/admin/login",
+			MaxAge:   int(db.DashboardLoginExpiry / time.Second),
+			Secure:   s.secureCookies,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+
+	return c.Render(http.StatusOK, "dashboardLogin.html", data.parse(c))
+}
+
+func (s *site) dashboardUseLogin(c echo.Context) (err error) {
+	cookie, err := c.Cookie(loginCSRFCookie)
+	if err != nil || cookie.Value == "" ||
+		subtle.ConstantTimeCompare([]byte(c.FormValue("csrf")), []byte(cookie.Value)) != 1 {
+		return c.Render(http.StatusForbidden, "dashboardLogin.html", (&renderData{
+			Conf:  s.Config,
+			Title: "Log in",
+			Error: "Invalid or missing CSRF token, open the login link again and try again.",
+		}).parse(c))
+	}
+
+	sess, token, err := s.db.UseDashboardLogin(c.FormValue("token"))
+	if err != nil {
+		if errors.Cause(err) != pgx.ErrNoRows {
+			log.Errorf("Error using dashboard login: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.Render(http.StatusUnauthorized, "dashboardLogin.html", (&renderData{
+			Conf:  s.Config,
+			Title: "Log in",
+			Error: "That login link is invalid, has expired, or was already used.",
+		}).parse(c))
+	}
+
+	log.Infof("%v (%v) logged in to the dashboard with scope %v", sess.Name, sess.UserID, sess.Scope)
+
+	c.SetCookie(&http.Cookie{
+		Name:     loginCSRFCookie,
+		Path:     "/admin/login",
+		MaxAge:   -1,
+		Secure:   s.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	c.SetCookie(&http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/admin",
+		Expires:  sess.Expires,
+		Secure:   s.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusSeeOther, "/admin")
+}
+
+func (s *site) dashboardLogout(c echo.Context) (err error) {
+	if cookie, err := c.Cookie(sessionCookie); err == nil {
+		err = s.db.EndDashboardSession(cookie.Value)
+		if err != nil {
+			log.Errorf("Error ending dashboard session: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/admin",
+		MaxAge:   -1,
+		Secure:   s.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// dashboardHomeEntries is the number of audit log entries shown on the dashboard's home page
+const dashboardHomeEntries = 10
+
+func (s *site) dashboard(c echo.Context) (err error) {
+	subs, err := s.db.PendingPronounSubmissions()
+	if err != nil {
+		log.Errorf("Error getting pronoun submissions: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	entries, err := s.auditLog.Entries("", 0, dashboardHomeEntries)
+	if err != nil {
+		log.Errorf("Error getting audit log: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Render(http.StatusOK, "dashboard.html", (&renderData{
+		Conf:        s.Config,
+		Title:       "Dashboard",
+		Submissions: subs,
+		Entries:     entries,
+	}).parse(c))
+}
+
+// flashCookie holds the message or error shown on the next dashboard page, see dashboardRedirect
+const flashCookie = "dashboard_flash"
+
+// dashboardRedirect redirects to path with a message or error, shown at the top of the page.
+// The message is kept in a cookie that's removed as soon as it's shown, so links can't show made up messages.
+func (s *site) dashboardRedirect(c echo.Context, path, key, msg string) error {
+	c.SetCookie(&http.Cookie{
+		Name:     flashCookie,
+		Value:    url.Values{key: {msg}}.Encode(),
+		Path:     "/admin",
+		MaxAge:   60,
+		Secure:   s.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusSeeOther, path)
+}
+
+// flash sets the message and error shown at the top of dashboard pages from the flash cookie, and removes it
+func (r *renderData) flash(c echo.Context) *renderData {
+	cookie, err := c.Cookie(flashCookie)
+	if err != nil {
+		return r
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     flashCookie,
+		Path:     "/admin",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	v, err := url.ParseQuery(cookie.Value)
+	if err != nil {
+		return r
+	}
+	r.Message = v.Get("message")
+	r.Error = v.Get("error")
+	return r
+}
+
+// idParam returns the id URL parameter, or 0 if it isn't a valid ID
+func idParam(c echo.Context) int {
+	id, _ := strconv.Atoi(c.Param("id"))
+	return id
+}
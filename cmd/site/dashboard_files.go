@@ -0,0 +1,67 @@
+package site
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dustin/go-humanize"
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+func (s *site) dashboardFiles(c echo.Context) (err error) {
+	files, err := s.db.Files()
+	if err != nil {
+		log.Errorf("Error getting files: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Render(http.StatusOK, "dashboardFiles.html", (&renderData{
+		Conf:  s.Config,
+		Title: "Files",
+		Files: files,
+	}).flash(c).parse(c))
+}
+
+// dashboardUpload adds an image file, with the same limits as the bot's `admin upload` command
+func (s *site) dashboardUpload(c echo.Context) (err error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return s.dashboardRedirect(c, "/admin/files", "error", "No file uploaded.")
+	}
+
+	if fh.Size > db.MaxFileSize {
+		return s.dashboardRedirect(c, "/admin/files", "error",
+			fmt.Sprintf("The file is too big (%v > 1 MB)", humanize.Bytes(uint64(fh.Size))))
+	}
+
+	contentType := db.ImageContentType(fh.Filename)
+	if contentType == "unknown" {
+		return s.dashboardRedirect(c, "/admin/files", "error", "The file you uploaded isn't an image.")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		log.Errorf("Error opening uploaded file: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		log.Errorf("Error reading uploaded file: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	file, err := s.db.AddFile(fh.Filename, contentType, data)
+	if err != nil {
+		log.Errorf("Error adding file: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	log.Infof("%v (%v) uploaded file %v (%v) on the dashboard", session(c).Name, session(c).UserID, file.Filename, file.ID)
+
+	return s.dashboardRedirect(c, "/admin/files", "message", fmt.Sprintf("File added with ID %v!", file.ID))
+}
@@ -0,0 +1,82 @@
+package site
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// logPageSize is the number of audit log entries on each page of the dashboard's log
+const logPageSize = 50
+
+func (s *site) dashboardLog(c echo.Context) (err error) {
+	subject := auditlog.EntrySubject(c.QueryParam("subject"))
+	switch subject {
+	case "", auditlog.TermEntry, auditlog.PronounsEntry, auditlog.ExplanationEntry:
+	default:
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	before, _ := strconv.ParseInt(c.QueryParam("before"), 10, 64)
+
+	entries, err := s.auditLog.Entries(subject, before, logPageSize)
+	if err != nil {
+		log.Errorf("Error getting audit log: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	var next int64
+	if len(entries) == logPageSize {
+		next = entries[len(entries)-1].ID
+	}
+
+	return c.Render(http.StatusOK, "dashboardLog.html", (&renderData{
+		Conf:        s.Config,
+		Title:       "Audit log",
+		Entries:     entries,
+		NextEntries: next,
+		Subject:     string(subject),
+	}).parse(c))
+}
+
+// entryName returns the name of the term, pronoun set, or explanation an audit log entry is for
+func entryName(e auditlog.Entry) string {
+	switch e.Subject {
+	case auditlog.TermEntry:
+		// for deleted terms, this is the term before it was deleted
+		t, _ := e.AfterTerm()
+		return t.Name
+	case auditlog.PronounsEntry:
+		p, _ := e.AfterPronouns()
+		return p.String()
+	case auditlog.ExplanationEntry:
+		ex, _ := e.AfterExplanation()
+		return ex.Name
+	}
+	return ""
+}
+
+// entryChanges returns the changes to a term in an audit log entry, or nil if it isn't a term entry
+func entryChanges(e auditlog.Entry) []db.FieldChange {
+	if e.Subject != auditlog.TermEntry || e.Action == auditlog.DeleteAction {
+		return nil
+	}
+
+	after, err := e.AfterTerm()
+	if err != nil {
+		return nil
+	}
+	if e.Action == auditlog.CreateAction {
+		return db.TermChanges(&db.Term{}, &after)
+	}
+
+	before, err := e.BeforeTerm()
+	if err != nil {
+		return nil
+	}
+	return db.TermChanges(&before, &after)
+}
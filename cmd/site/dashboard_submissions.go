@@ -0,0 +1,133 @@
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+func (s *site) dashboardSubmissions(c echo.Context) (err error) {
+	subs, err := s.db.PendingPronounSubmissions()
+	if err != nil {
+		log.Errorf("Error getting pronoun submissions: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Render(http.StatusOK, "dashboardSubmissions.html", (&renderData{
+		Conf:        s.Config,
+		Title:       "Pronoun submissions",
+		Submissions: subs,
+	}).flash(c).parse(c))
+}
+
+// dashboardResolveSubmission approves, rejects, or merges a pronoun submission, the same way as the buttons on the bot's submission messages
+func (s *site) dashboardResolveSubmission(c echo.Context) (err error) {
+	sess := session(c)
+	id := idParam(c)
+
+	var (
+		sub db.PronounSubmission
+		// set is the approved set, into is the set the submission was merged into
+		set, into *db.PronounSet
+		msg       string
+	)
+
+	switch c.Param("action") {
+	case "approve":
+		sub, set, err = s.db.ApprovePronounSubmission(id, sess.UserID)
+		msg = fmt.Sprintf("Approved %v.", sub.Set())
+	case "reject":
+		reason := strings.TrimSpace(c.FormValue("reason"))
+		if reason == "" {
+			return s.dashboardRedirect(c, "/admin/submissions", "error", "You need to give a reason for rejecting a submission.")
+		}
+
+		sub, err = s.db.RejectPronounSubmission(id, sess.UserID, reason)
+		msg = fmt.Sprintf("Rejected %v.", sub.Set())
+	case "merge":
+		into, err = s.db.FindPronounSet(c.FormValue("set"))
+		if err != nil {
+			return s.dashboardRedirect(c, "/admin/submissions", "error", "Couldn't find that pronoun set. Give either its ID or enough forms to identify it.")
+		}
+
+		sub, err = s.db.MergePronounSubmission(id, sess.UserID, into.ID)
+		msg = fmt.Sprintf("Merged %v into %v.", sub.Set(), into)
+	default:
+		return c.NoContent(http.StatusNotFound)
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrSubmissionResolved) {
+			return s.dashboardRedirect(c, "/admin/submissions", "error", "That submission has already been resolved.")
+		}
+		var exists *db.ErrPronounExists
+		if errors.As(err, &exists) {
+			return s.dashboardRedirect(c, "/admin/submissions", "error", fmt.Sprintf("That set is already in the database with ID %v. Merge the submission into it instead.", exists.ID))
+		}
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		log.Errorf("Error resolving pronoun submission %v: %v", id, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	if set != nil {
+		_, err = s.auditLog.SendLog(set.ID, auditlog.PronounsEntry, auditlog.CreateAction, nil, set, sess.UserID, nil)
+		if err != nil {
+			log.Errorf("Error sending audit log for pronoun set %v: %v", set.ID, err)
+		}
+	}
+
+	s.submissionResolved(sess, sub, into)
+
+	return s.dashboardRedirect(c, "/admin/submissions", "message", msg)
+}
+
+// submissionResolved removes the buttons from the submission's message in the pronoun channel, and DMs the submitter.
+// Errors are only logged, as the submission has already been resolved.
+func (s *site) submissionResolved(sess *db.DashboardSession, sub db.PronounSubmission, into *db.PronounSet) {
+	if s.state == nil {
+		return
+	}
+
+	if s.pronounChannel.IsValid() && sub.MessageID.IsValid() {
+		content := fmt.Sprintf("Resolved (%v) by %v on the dashboard.", sub.Status, sess.UserID.Mention())
+		_, err := s.state.EditMessageComplex(s.pronounChannel, sub.MessageID, api.EditMessageData{
+			Content:         option.NewNullableString(content),
+			Components:      &discord.ContainerComponents{},
+			AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+		})
+		if err != nil {
+			log.Errorf("Error updating pronoun submission message: %v", err)
+		}
+	}
+
+	// submissions carried over from the old system don't have a submitter
+	if !sub.UserID.IsValid() {
+		return
+	}
+
+	ch, err := s.state.CreatePrivateChannel(sub.UserID)
+	if err != nil {
+		log.Errorf("Error creating DM channel for %v: %v", sub.UserID, err)
+		return
+	}
+
+	_, err = s.state.SendMessageComplex(ch.ID, api.SendMessageData{
+		Content:         sub.ResolvedMessage(into),
+		AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+	})
+	if err != nil {
+		// the submitter might have DMs closed
+		log.Debugf("Error sending submission DM to %v: %v", sub.UserID, err)
+	}
+}
@@ -0,0 +1,299 @@
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/admin/auditlog"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
+)
+
+// termFlag is a flag that can be set in the term editor
+type termFlag struct {
+	Flag search.TermFlag
+	Name string
+}
+
+var termFlags = []termFlag{
+	{search.FlagSearchHidden, "Hidden from search"},
+	{search.FlagRandomHidden, "Hidden from random"},
+	{search.FlagListHidden, "Hidden from lists"},
+	{search.FlagShowWarning, "Show warning"},
+	{search.FlagDisputed, "Disputed"},
+}
+
+func (s *site) dashboardTerms(c echo.Context) (err error) {
+	page, ok := pageParam(c)
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	// hidden terms can be edited too, so they're included
+	terms, err := s.db.GetTerms(0)
+	if err != nil {
+		log.Errorf("Error getting terms: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if q != "" {
+		terms = filterTerms(terms, q)
+	}
+
+	terms, pages := db.Paginate(terms, page, s.pageSize)
+
+	return c.Render(http.StatusOK, "dashboardTerms.html", (&renderData{
+		Conf:        s.Config,
+		Title:       "Terms",
+		Terms:       terms,
+		SearchQuery: q,
+		Page:        page,
+		Pages:       pages,
+		PageURL:     "/admin/terms?q=" + url.QueryEscape(q) + "&page=",
+	}).flash(c).parse(c))
+}
+
+// filterTerms returns the terms with q in their name or aliases, or with q as their ID
+func filterTerms(terms []*db.Term, q string) (out []*db.Term) {
+	q = strings.ToLower(q)
+	id, _ := strconv.Atoi(q)
+
+	for _, t := range terms {
+		if t.ID == id || strings.Contains(strings.ToLower(t.Name), q) {
+			out = append(out, t)
+			continue
+		}
+
+		for _, a := range t.Aliases {
+			if strings.Contains(strings.ToLower(a), q) {
+				out = append(out, t)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// dashboardTerm returns the term being edited, or a new term if the id parameter isn't set
+func (s *site) dashboardTerm(c echo.Context) (t *db.Term, err error) {
+	if c.Param("id") == "" {
+		return &db.Term{Source: "Unknown"}, nil
+	}
+	return s.db.GetTerm(idParam(c))
+}
+
+func (s *site) dashboardEditTerm(c echo.Context) (err error) {
+	t, err := s.dashboardTerm(c)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		log.Errorf("Error getting term: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return s.renderTermEditor(c, http.StatusOK, t, (&renderData{}).flash(c))
+}
+
+func (s *site) renderTermEditor(c echo.Context, status int, t *db.Term, data *renderData) error {
+	cats, err := s.db.GetCategories()
+	if err != nil {
+		log.Errorf("Error getting categories: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		return cats[i].Name < cats[j].Name
+	})
+
+	data.Conf = s.Config
+	data.Term = t
+	data.AllCategories = cats
+	data.Title = "New term"
+	if t.ID != 0 {
+		data.Title = "Editing " + t.Name
+	}
+
+	return c.Render(status, "dashboardTerm.html", data.parse(c))
+}
+
+// applyTermForm applies the term editor's form to t
+func applyTermForm(c echo.Context, t *db.Term) {
+	t.Name = strings.TrimSpace(c.FormValue("name"))
+	t.Category, _ = strconv.Atoi(c.FormValue("category"))
+	t.Description = strings.TrimSpace(c.FormValue("description"))
+	t.Source = strings.TrimSpace(c.FormValue("source"))
+	t.Note = strings.TrimSpace(c.FormValue("note"))
+	t.ContentWarnings = strings.TrimSpace(c.FormValue("content_warnings"))
+	t.ImageURL = strings.TrimSpace(c.FormValue("image_url"))
+
+	// aliases and tags are one per line
+	t.Aliases = formLines(c.FormValue("aliases"))
+	t.DisplayTags = formLines(c.FormValue("tags"))
+	t.Tags = nil
+	for _, tag := range t.DisplayTags {
+		t.Tags = append(t.Tags, strings.ToLower(tag))
+	}
+
+	// flags not in the editor are kept as they are
+	for _, f := range termFlags {
+		t.Flags &^= f.Flag
+	}
+	params, _ := c.FormParams()
+	for _, v := range params["flag"] {
+		f, _ := strconv.Atoi(v)
+		for _, tf := range termFlags {
+			if search.TermFlag(f) == tf.Flag {
+				t.Flags |= tf.Flag
+			}
+		}
+	}
+}
+
+// formLines returns the non-empty lines of a textarea
+func formLines(s string) (lines []string) {
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func (s *site) dashboardSaveTerm(c echo.Context) (err error) {
+	before, err := s.dashboardTerm(c)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		log.Errorf("Error getting term: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	t := *before
+	applyTermForm(c, &t)
+
+	// the editor is shown again with the unsaved changes if they aren't valid
+	err = db.ValidateTerm(&t)
+	if err != nil {
+		return s.renderTermEditor(c, http.StatusBadRequest, &t, &renderData{Error: err.Error()})
+	}
+	cat := s.db.CategoryFromID(t.Category)
+	if cat.ID == 0 {
+		return s.renderTermEditor(c, http.StatusBadRequest, &t, &renderData{Error: "category: category doesn't exist"})
+	}
+	t.CategoryName = cat.Name
+
+	for _, tag := range t.DisplayTags {
+		err = s.db.AddTag(tag)
+		if err != nil {
+			log.Errorf("Error adding tag %q: %v", tag, err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	action := auditlog.UpdateAction
+	if t.ID == 0 {
+		action = auditlog.CreateAction
+
+		_, err = s.db.AddTerm(&t)
+		if err != nil {
+			log.Errorf("Error adding term: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	} else {
+		err = s.db.UpdateTerm(&t)
+		if err != nil {
+			log.Errorf("Error updating term %v: %v", t.ID, err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	after, err := s.db.GetTerm(t.ID)
+	if err != nil {
+		log.Errorf("Error getting term %v: %v", t.ID, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	if action == auditlog.CreateAction {
+		s.sendLog(c, after.ID, auditlog.TermEntry, action, nil, after)
+	} else {
+		s.sendLog(c, after.ID, auditlog.TermEntry, action, before, after)
+	}
+
+	return s.dashboardRedirect(c, fmt.Sprintf("/admin/terms/%v", after.ID), "message", "Saved!")
+}
+
+func (s *site) dashboardDeleteTerm(c echo.Context) (err error) {
+	t, err := s.db.GetTerm(idParam(c))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		log.Errorf("Error getting term: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	err = s.db.RemoveTerm(t.ID)
+	if err != nil {
+		log.Errorf("Error deleting term %v: %v", t.ID, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	s.sendLog(c, t.ID, auditlog.TermEntry, auditlog.DeleteAction, t, nil)
+
+	return s.dashboardRedirect(c, "/admin/terms", "message", "Deleted "+t.Name+".")
+}
+
+// dashboardPreview renders the term editor's form as both the term's page and its embed in the bot.
+// The editor's script sends the form here whenever it changes.
+func (s *site) dashboardPreview(c echo.Context) (err error) {
+	id, _ := strconv.Atoi(c.FormValue("id"))
+
+	t := &db.Term{Source: "Unknown"}
+	if id != 0 {
+		t, err = s.db.GetTerm(id)
+		if err != nil {
+			if errors.Cause(err) == pgx.ErrNoRows {
+				return c.NoContent(http.StatusNotFound)
+			}
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	applyTermForm(c, t)
+	t.CategoryName = s.db.CategoryFromID(t.Category).Name
+	// validation errors are shown with the preview, so they're noticed before saving
+	var errMsg string
+	if err = db.ValidateTerm(t); err != nil {
+		errMsg = err.Error()
+	}
+
+	embed := s.db.TermEmbed(t)
+	s.prepareTerm(t)
+
+	return c.Render(http.StatusOK, "dashboardPreview.html", (&renderData{
+		Conf:  s.Config,
+		Term:  t,
+		Embed: &embed,
+		Error: errMsg,
+	}).parse(c))
+}
+
+// sendLog adds an audit log entry for a change made on the dashboard, attributed to the logged in user
+func (s *site) sendLog(c echo.Context, id int, subject auditlog.EntrySubject, action auditlog.ActionType, before, after interface{}) {
+	var reason *string
+	if r := strings.TrimSpace(c.FormValue("reason")); r != "" {
+		reason = &r
+	}
+
+	s.auditLog.LogChange(id, subject, action, before, after, session(c).UserID, reason)
+}
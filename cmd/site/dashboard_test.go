@@ -0,0 +1,78 @@
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestDashboardFlash(t *testing.T) {
+	e := echo.New()
+	s := &site{}
+
+	rec := httptest.NewRecorder()
+	err := s.dashboardRedirect(e.NewContext(httptest.NewRequest(http.MethodPost, "/admin/terms/1", nil), rec), "/admin/terms/1", "message", "Saved!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/admin/terms/1" {
+		t.Fatalf("expected redirect to /admin/terms/1, got %q", loc)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != flashCookie {
+		t.Fatalf("expected a flash cookie, got %v", cookies)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/terms/1", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	r := (&renderData{}).flash(e.NewContext(req, rec))
+	if r.Message != "Saved!" || r.Error != "" {
+		t.Fatalf("expected message %q, got message %q and error %q", "Saved!", r.Message, r.Error)
+	}
+	if c := rec.Result().Cookies(); len(c) != 1 || c[0].MaxAge >= 0 {
+		t.Fatalf("expected the flash cookie to be removed, got %v", c)
+	}
+
+	// messages in the query string aren't shown
+	rec = httptest.NewRecorder()
+	r = (&renderData{}).flash(e.NewContext(httptest.NewRequest(http.MethodGet, "/admin?message=Saved!&error=Oops", nil), rec))
+	if r.Message != "" || r.Error != "" {
+		t.Fatalf("expected no message from the query string, got message %q and error %q", r.Message, r.Error)
+	}
+}
+
+// TestDashboardLoginCSRF checks that logging in needs the CSRF token from the login page's cookie
+func TestDashboardLoginCSRF(t *testing.T) {
+	e := echo.New()
+	e.Renderer = newRenderer()
+	s := &site{}
+
+	for _, tc := range []struct {
+		name, cookie, csrf string
+	}{
+		{"no cookie", "", "token"},
+		{"different cookie", "other", "token"},
+		{"empty token", "", ""},
+	} {
+		form := url.Values{"token": {"login"}, "csrf": {tc.csrf}}
+		req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: loginCSRFCookie, Value: tc.cookie})
+		}
+
+		rec := httptest.NewRecorder()
+		if err := s.dashboardUseLogin(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("%v: %v", tc.name, err)
+		}
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%v: expected status 403, got %v", tc.name, rec.Code)
+		}
+	}
+}
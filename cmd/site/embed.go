@@ -0,0 +1,170 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/db"
+)
+
+// Default and minimum sizes of embedded term iframes, in pixels
+const (
+	embedWidth     = 500
+	embedHeight    = 350
+	embedMinWidth  = 200
+	embedMinHeight = 150
+)
+
+// embedTheme returns a valid embed theme, "light", "dark", or "" to follow the reader's preference
+func embedTheme(s string) string {
+	switch s {
+	case "light", "dark":
+		return s
+	}
+	return ""
+}
+
+// embedURL returns the path to a term's embed page, with the theme if it's set
+func embedURL(t *db.Term, theme string) string {
+	u := "/embed" + termURL(t)
+	if theme = embedTheme(theme); theme != "" {
+		u += "?theme=" + theme
+	}
+	return u
+}
+
+func (s *site) embedTerm(c echo.Context) (err error) {
+	t, canonical, err := s.lookupTerm(c.Param("term"))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	theme := embedTheme(c.QueryParam("theme"))
+	if !canonical {
+		return c.Redirect(http.StatusMovedPermanently, embedURL(t, theme))
+	}
+
+	s.prepareTerm(t)
+
+	return c.Render(http.StatusOK, "embed.html", (&renderData{
+		Conf:      s.Config,
+		Term:      t,
+		Canonical: s.Config.BaseURL + termURL(t),
+		Theme:     theme,
+	}).parse(c))
+}
+
+type oembedResponse struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	CacheAge     int    `json:"cache_age"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// oembed implements the oEmbed spec (https://oembed.com/) for term pages and term embeds.
+// Only the JSON format is supported.
+func (s *site) oembed(c echo.Context) (err error) {
+	if f := c.QueryParam("format"); f != "" && f != "json" {
+		return c.NoContent(http.StatusNotImplemented)
+	}
+
+	param, ok := s.oembedTermParam(c.QueryParam("url"))
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	t, _, err := s.lookupTermName(param)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	width := oembedSize(c.QueryParam("maxwidth"), embedWidth, embedMinWidth)
+	height := oembedSize(c.QueryParam("maxheight"), embedHeight, embedMinHeight)
+
+	src := s.Config.BaseURL + embedURL(t, c.QueryParam("theme"))
+
+	return c.JSON(http.StatusOK, oembedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        t.Name,
+		ProviderName: s.Config.SiteName,
+		ProviderURL:  s.Config.BaseURL,
+		CacheAge:     3600,
+		HTML: fmt.Sprintf(`<iframe src="%v" width="%v" height="%v" title="%v" style="border: 0;" loading="lazy"></iframe>`,
+			html.EscapeString(src), width, height, html.EscapeString(t.Name+" | "+s.Config.SiteName)),
+		Width:  width,
+		Height: height,
+	})
+}
+
+// oembedTermParam returns the unescaped term part of a term page or embed URL, if u is one on this site
+func (s *site) oembedTermParam(u string) (param string, ok bool) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "", false
+	}
+	base, err := url.Parse(s.Config.BaseURL)
+	if err != nil || !strings.EqualFold(pu.Host, base.Host) {
+		return "", false
+	}
+
+	p := pu.EscapedPath()
+	for _, prefix := range []string{"/term/", "/embed/term/"} {
+		if strings.HasPrefix(p, prefix) {
+			param = strings.TrimPrefix(p, prefix)
+			if param == "" || strings.Contains(param, "/") {
+				return "", false
+			}
+
+			param, err = url.PathUnescape(param)
+			return param, err == nil
+		}
+	}
+	return "", false
+}
+
+// oembedSize returns def, or max if it's smaller, but never less than min
+func oembedSize(max string, def, min int) int {
+	n, err := strconv.Atoi(max)
+	if err != nil || n <= 0 || n >= def {
+		return def
+	}
+	if n < min {
+		return min
+	}
+	return n
+}
+
+// embedScript serves static/embed.js, called with the API and site URLs from the config
+func (s *site) embedScript(c echo.Context) (err error) {
+	b, err := fs.ReadFile(staticFS, "static/embed.js")
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	api, _ := json.Marshal(strings.TrimSuffix(s.Config.APIURL, "/"))
+	base, _ := json.Marshal(strings.TrimSuffix(s.Config.BaseURL, "/"))
+
+	b = append(b, fmt.Sprintf("(%s, %s);\n", api, base)...)
+	return c.Blob(http.StatusOK, "application/javascript; charset=utf-8", b)
+}
@@ -0,0 +1,36 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/termora/berry/common"
+)
+
+func TestOEmbedTermParam(t *testing.T) {
+	s := &site{Config: common.SiteConfig{BaseURL: "https://example.com"}}
+
+	for u, want := range map[string]string{
+		"https://example.com/term/demigirl":           "demigirl",
+		"https://example.com/embed/term/12":           "12",
+		"https://example.com/term/caf%C3%A9":          "café",
+		"https://example.com/term/two%20words":        "two words",
+		"https://example.com/embed/term/100%25-maybe": "100%-maybe",
+		"https://example.com/term/he%2Fhim":           "he/him",
+	} {
+		got, ok := s.oembedTermParam(u)
+		if !ok || got != want {
+			t.Errorf("%v: expected %q, got %q (ok: %v)", u, want, got, ok)
+		}
+	}
+
+	for _, u := range []string{
+		"https://example.org/term/demigirl",
+		"https://example.com/term/",
+		"https://example.com/term/a/b",
+		"https://example.com/tag/demigirl",
+	} {
+		if got, ok := s.oembedTermParam(u); ok {
+			t.Errorf("%v: expected no term, got %q", u, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package site
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/pkg/errors"
+	"github.com/russross/blackfriday/v2"
+	"github.com/termora/berry/common/log"
+)
+
+// explanationURL returns the path to an explanation's page
+func explanationURL(name string) string {
+	return "/explanations/" + url.PathEscape(name)
+}
+
+func (s *site) explanations(c echo.Context) (err error) {
+	ex, err := s.db.GetAllExplanations()
+	if err != nil {
+		log.Errorf("Error getting explanations: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	sort.Slice(ex, func(i, j int) bool {
+		return strings.ToLower(ex[i].Name) < strings.ToLower(ex[j].Name)
+	})
+
+	return c.Render(http.StatusOK, "explanations.html", (&renderData{
+		Conf:         s.Config,
+		Title:        "Explanations",
+		Description:  "Short explanations of common topics, also available through the bot's explain command.",
+		Explanations: ex,
+		Canonical:    s.Config.BaseURL + "/explanations",
+	}).parse(c))
+}
+
+func (s *site) explanation(c echo.Context) (err error) {
+	name, err := url.PathUnescape(c.Param("name"))
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+
+	e, err := s.db.ExplanationByName(name)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.Render(http.StatusNotFound, "404.html", (&renderData{
+				Conf: s.Config,
+			}).parse(c))
+		}
+		log.Errorf("Error getting explanation %q: %v", name, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	// aliases and differently cased names all redirect to the explanation's name
+	if name != e.Name {
+		return c.Redirect(http.StatusMovedPermanently, explanationURL(e.Name))
+	}
+
+	md := template.HTML(bluemonday.UGCPolicy().SanitizeBytes(
+		blackfriday.Run([]byte(s.db.LinkTerms(e.Description)),
+			blackfriday.WithExtensions(blackfriday.Autolink|blackfriday.Strikethrough|blackfriday.HardLineBreak))))
+
+	return c.Render(http.StatusOK, "explanation.html", (&renderData{
+		Conf:        s.Config,
+		Title:       e.Name,
+		Description: e.Description,
+		Explanation: e,
+		MD:          md,
+		Canonical:   s.Config.BaseURL + explanationURL(e.Name),
+	}).parse(c))
+}
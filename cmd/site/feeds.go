@@ -0,0 +1,33 @@
+package site
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/common/log"
+)
+
+func (s *site) feed(c echo.Context) (err error) {
+	var data, ctype string
+	switch c.Param("feed") {
+	case "feed.rss", "rss.xml":
+		ctype = "application/rss+xml"
+		data, err = s.feeds.RSS()
+	case "feed.atom", "atom.xml":
+		ctype = "application/atom+xml"
+		data, err = s.feeds.Atom()
+	case "feed.json":
+		ctype = "application/feed+json"
+		data, err = s.feeds.JSON()
+	default:
+		return c.Render(http.StatusNotFound, "404.html", (&renderData{
+			Conf: s.Config,
+		}).parse(c))
+	}
+	if err != nil {
+		log.Errorf("Error getting feed: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Blob(http.StatusOK, ctype, []byte(data))
+}
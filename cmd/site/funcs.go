@@ -1,14 +1,18 @@
 package site
 
 import (
+	"fmt"
 	"html/template"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
 	"github.com/termora/berry/db"
+	"github.com/termora/berry/db/search"
 )
 
 // HeadlineLen is the length of a headline (used in embeds)
@@ -29,19 +33,33 @@ func funcMap() template.FuncMap {
 		"timeToDate": func(t time.Time) string {
 			return t.Format("Mon January 02 2006")
 		},
-		"markdownParse": func(s string) template.HTML {
-			return template.HTML(bluemonday.UGCPolicy().SanitizeBytes(
-				blackfriday.Run(
-					[]byte(s),
-					blackfriday.WithExtensions(blackfriday.Autolink|blackfriday.Strikethrough|blackfriday.HardLineBreak))))
-		},
+		"markdownParse": markdownParse,
 		"sanitize": func(s string) template.HTML {
 			return template.HTML(bluemonday.UGCPolicy().Sanitize(s))
 		},
 		"resultsNum": func(s []*db.Term) int {
 			return len(s)
 		},
-		"title": strings.Title,
+		"title":   strings.Title,
+		"termURL": termURL,
+		"browseLetters": func() []string {
+			return db.BrowseLetters
+		},
+		"letterSlug":     db.LetterSlug,
+		"pronounURL":     pronounURL,
+		"withName":       withName,
+		"explanationURL": explanationURL,
+		"highlight":      highlight,
+		"searchSorts": func() []db.SearchSort {
+			return db.SearchSorts
+		},
+		"pronounLanguages": func() []db.PronounLanguage {
+			return db.PronounLanguages
+		},
+		"pronounLanguage": func(code string) db.PronounLanguage {
+			l, _ := db.PronounLanguageByCode(code)
+			return l
+		},
 		"pageStyle": func(darkMode string) template.CSS {
 			if darkMode == "false" {
 				return template.CSS("")
@@ -52,17 +70,67 @@ func funcMap() template.FuncMap {
 			}
 		},
 		"headline": func(in string) template.HTML {
-			slice := strings.Split(in, " ")
-			buf := slice[0]
-			for _, s := range slice[1:] {
-				if len(buf) > HeadlineLen {
-					buf += "..."
-					break
-				}
-				buf += " " + s
-			}
-			return template.HTML(
-				strings.TrimSpace(buf))
+			return template.HTML(headline(in))
 		},
+		"termSummary": termSummary,
+		"historyURL":  historyURL,
+		"diffHTML":    diffHTML,
+		"fieldName":   db.FieldName,
+		"warningText": func() string {
+			return db.WarningText
+		},
+		"termFlags": func() []termFlag {
+			return termFlags
+		},
+		"hasFlag": func(t *db.Term, f search.TermFlag) bool {
+			return t.Flags&f == f
+		},
+		"entryName":       entryName,
+		"entryChanges":    entryChanges,
+		"discordMarkdown": discordMarkdown,
+		"embedColour": func(c discord.Color) template.CSS {
+			return template.CSS(fmt.Sprintf("#%06x", uint32(c)))
+		},
+	}
+}
+
+func markdownParse(s string) template.HTML {
+	return template.HTML(bluemonday.UGCPolicy().SanitizeBytes(
+		blackfriday.Run(
+			[]byte(s),
+			blackfriday.WithExtensions(blackfriday.Autolink|blackfriday.Strikethrough|blackfriday.HardLineBreak))))
+}
+
+var spoilerRegexp = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+
+// discordMarkdown renders markdown the way it's shown in the bot's embeds, including spoilers
+func discordMarkdown(s string) template.HTML {
+	// the markdown is sanitized first, so the spoiler tags are the only HTML added afterwards
+	html := string(markdownParse(s))
+	return template.HTML(spoilerRegexp.ReplaceAllString(html, `<span class="spoiler">$1</span>`))
+}
+
+// headline shortens in to about HeadlineLen characters, at a word boundary
+func headline(in string) string {
+	slice := strings.Split(in, " ")
+	buf := slice[0]
+	for _, s := range slice[1:] {
+		if len(buf) > HeadlineLen {
+			buf += "..."
+			break
+		}
+		buf += " " + s
+	}
+	return strings.TrimSpace(buf)
+}
+
+var markdownLinkRegexp = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// termSummary returns a short plain text summary of a term for link previews.
+// Terms with content warnings only show the warning, as previews can't be hidden behind a spoiler.
+func termSummary(t *db.Term) string {
+	if t.ContentWarnings != "" {
+		return "Content warning: " + headline(markdownLinkRegexp.ReplaceAllString(t.ContentWarnings, "$1"))
 	}
+	return headline(markdownLinkRegexp.ReplaceAllString(t.Description, "$1"))
 }
@@ -0,0 +1,64 @@
+package site
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+func (s *site) termHistory(c echo.Context) (err error) {
+	t, canonical, err := s.lookupTerm(c.Param("term"))
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	if !canonical {
+		return c.Redirect(http.StatusMovedPermanently, historyURL(t))
+	}
+
+	revs, err := s.db.TermHistory(t.ID)
+	if err != nil {
+		log.Errorf("Error getting history for term %v: %v", t.ID, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Render(http.StatusOK, "history.html", (&renderData{
+		Conf:        s.Config,
+		Term:        t,
+		Revisions:   revs,
+		Title:       "History of " + t.Name,
+		Description: "Public changes to " + t.Name + " on " + s.Config.SiteName + ".",
+		Canonical:   s.Config.BaseURL + historyURL(t),
+	}).parse(c))
+}
+
+// historyURL returns the path to a term's history page
+func historyURL(t *db.Term) string {
+	return termURL(t) + "/history"
+}
+
+// diffHTML renders a text diff, with insertions and deletions marked
+func diffHTML(parts []db.DiffPart) template.HTML {
+	var b strings.Builder
+	for _, p := range parts {
+		switch p.Op {
+		case db.DiffInsert:
+			b.WriteString("<ins>" + html.EscapeString(p.Text) + "</ins>")
+		case db.DiffDelete:
+			b.WriteString("<del>" + html.EscapeString(p.Text) + "</del>")
+		default:
+			b.WriteString(html.EscapeString(p.Text))
+		}
+	}
+	return template.HTML(b.String())
+}
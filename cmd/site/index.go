@@ -7,7 +7,7 @@ import (
 )
 
 func (s *site) index(c echo.Context) (err error) {
-	tags, err := s.db.Tags()
+	tags, err := s.db.TagSlugs()
 	if err != nil {
 		return c.NoContent(http.StatusInternalServerError)
 	}
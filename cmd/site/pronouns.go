@@ -0,0 +1,167 @@
+package site
+
+import (
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/termora/berry/commands/pronouns/examples"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// pronounURL returns the path to a page showing the given sets
+func pronounURL(sets ...*db.PronounSet) string {
+	var parts []string
+	for _, set := range sets {
+		forms := set.FormList()
+		escaped := make([]string, len(forms))
+		for i := range forms {
+			escaped[i] = url.PathEscape(forms[i])
+		}
+		parts = append(parts, strings.Join(escaped, "/"))
+	}
+
+	u := "/pronouns/" + strings.Join(parts, "+")
+	if len(sets) > 0 && sets[0].Lang() != db.DefaultLanguage {
+		u += "?language=" + url.QueryEscape(sets[0].Lang())
+	}
+	return u
+}
+
+// withName adds a name to a pronoun page's URL, if it isn't empty
+func withName(name, u string) string {
+	if name == "" {
+		return u
+	}
+	if strings.Contains(u, "?") {
+		return u + "&name=" + url.QueryEscape(name)
+	}
+	return u + "?name=" + url.QueryEscape(name)
+}
+
+// pronounList lists all pronoun sets, sorted alphabetically or by uses like the bot's list-pronouns command
+func (s *site) pronounList(c echo.Context) (err error) {
+	order := db.AlphabeticPronounOrder
+	sort := c.QueryParam("sort")
+	if sort == "uses" {
+		order = db.UsesPronounOrder
+	} else {
+		sort = "alphabetical"
+	}
+
+	lang := c.QueryParam("language")
+	title := "List of pronouns"
+	if lang != "" {
+		l, ok := db.PronounLanguageByCode(lang)
+		if !ok {
+			return c.Render(http.StatusNotFound, "404.html", (&renderData{
+				Conf: s.Config,
+			}).parse(c))
+		}
+		lang = l.Code
+		title = "List of " + l.Name + " pronouns"
+	}
+
+	sets, err := s.db.LanguagePronouns(lang, order)
+	if err != nil {
+		log.Errorf("Error getting pronouns: %v", err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	var users map[int]int64
+	if order == db.UsesPronounOrder {
+		users, err = s.db.PronounUserCounts()
+		if err != nil {
+			log.Errorf("Error getting pronoun user counts: %v", err)
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	return c.Render(http.StatusOK, "pronouns.html", (&renderData{
+		Conf:         s.Config,
+		Title:        title,
+		Description:  "Example sentences for " + english.Plural(len(sets), "pronoun set", "pronoun sets") + ".",
+		Pronouns:     sets,
+		PronounUsers: users,
+		Language:     lang,
+		Sort:         sort,
+		Canonical:    s.Config.BaseURL + "/pronouns",
+	}).parse(c))
+}
+
+// pronouns shows example sentences for the pronouns in the path, in the same format as the bot's pronouns command
+func (s *site) pronouns(c echo.Context) (err error) {
+	notFound := func() error {
+		return c.Render(http.StatusNotFound, "404.html", (&renderData{
+			Conf: s.Config,
+		}).parse(c))
+	}
+
+	input, err := url.PathUnescape(strings.Trim(c.Param("*"), "/"))
+	if err != nil {
+		return notFound()
+	}
+	if input == "" {
+		return c.Redirect(http.StatusFound, "/pronouns")
+	}
+
+	lang, ok := db.PronounLanguageByCode(c.QueryParam("language"))
+	if !ok {
+		return notFound()
+	}
+
+	sets, mixed, err := s.db.ParsePronouns(lang.Code, input)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows || err == db.ErrTooManyForms || err == db.ErrNoForms {
+			return notFound()
+		}
+		log.Errorf("Error getting pronouns %q: %v", input, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	name := strings.TrimSpace(c.QueryParam("name"))
+
+	// the input matches more than one set, so let the user pick one
+	if len(sets) > 1 && !mixed {
+		return c.Render(http.StatusOK, "pronouns.html", (&renderData{
+			Conf:     s.Config,
+			Title:    "Pronouns matching " + input,
+			Pronouns: sets,
+			Name:     name,
+			Language: lang.Code,
+		}).parse(c))
+	}
+
+	// the examples are markdown, so the name is escaped to not add any HTML to the page
+	ex, err := examples.Render(sets, html.EscapeString(name))
+	if err != nil {
+		log.Errorf("Error rendering pronouns %q: %v", input, err)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	var forms []string
+	for _, set := range sets {
+		forms = append(forms, set.String())
+	}
+	desc := "Example sentences using " + strings.Join(forms, " and ") + " pronouns"
+	if name != "" {
+		desc += " for " + name
+	}
+
+	return c.Render(http.StatusOK, "pronoun.html", (&renderData{
+		Conf:        s.Config,
+		Title:       strings.Title(examples.Title(sets)),
+		Description: desc + ".",
+		Pronouns:    sets,
+		Examples:    ex,
+		Name:        name,
+		Language:    lang.Code,
+		Canonical:   s.Config.BaseURL + pronounURL(sets...),
+	}).parse(c))
+}
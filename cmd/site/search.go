@@ -1,27 +1,93 @@
 package site
 
 import (
+	"html"
 	"html/template"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/microcosm-cc/bluemonday"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
 )
 
 func (s *site) search(c echo.Context) (err error) {
 	q := template.HTML(bluemonday.UGCPolicy().Sanitize(c.QueryParam("q")))
-	terms, err := s.db.Search(c.QueryParam("q"), 0, []string{})
 
-	if err != nil || len(terms) == 0 {
+	filter, err := s.db.ParseSearchFilter(c.QueryParams())
+	if err != nil {
+		return c.Render(http.StatusBadRequest, "noQuery.html", (&renderData{
+			Conf:        s.Config,
+			Query:       q,
+			SearchQuery: c.QueryParam("q"),
+			Filter:      filter,
+			FilterError: err.Error(),
+		}).parse(c))
+	}
+
+	terms, err := s.db.FilteredSearch(c.QueryParam("q"), filter)
+	if err != nil {
+		terms = nil
+	}
+
+	// explanations don't have categories or tags, so they're only shown without those filters
+	var ex []*db.Explanation
+	if filter.Category == 0 && len(filter.Ignore) == 0 {
+		ex, err = s.db.SearchExplanations(c.QueryParam("q"))
+		if err != nil {
+			// term results are still useful on their own
+			log.Errorf("Error searching explanations: %v", err)
+		}
+	}
+
+	if len(terms) == 0 && len(ex) == 0 {
 		return c.Render(http.StatusNotFound, "noQuery.html", (&renderData{
-			Conf:  s.Config,
-			Query: q,
+			Conf:        s.Config,
+			Query:       q,
+			SearchQuery: c.QueryParam("q"),
+			Filter:      filter,
 		}).parse(c))
 	}
 
 	return c.Render(http.StatusOK, "results.html", (&renderData{
-		Conf:  s.Config,
-		Terms: terms,
-		Query: q,
+		Conf:         s.Config,
+		Terms:        terms,
+		Explanations: ex,
+		Query:        q,
+		SearchQuery:  c.QueryParam("q"),
+		Filter:       filter,
 	}).parse(c))
 }
+
+var (
+	// highlightRegexp matches highlighted words in a headline,
+	// marked with ** by the postgres searcher and <mark> by Typesense
+	highlightRegexp = regexp.MustCompile(`\*\*(.+?)\*\*|<mark>(.*?)</mark>`)
+	// headlineLinkRegexp matches [[term]] and [[display|term]] links, which are shown as just their display text
+	headlineLinkRegexp = regexp.MustCompile(`\[\[(.*?)(\|.*?)?\]\]`)
+)
+
+// highlight escapes a search result headline, wrapping the matched words in <mark>
+func highlight(headline string) template.HTML {
+	headline = headlineLinkRegexp.ReplaceAllString(headline, "$1")
+
+	var b strings.Builder
+	last := 0
+	for _, m := range highlightRegexp.FindAllStringSubmatchIndex(headline, -1) {
+		b.WriteString(html.EscapeString(headline[last:m[0]]))
+
+		var word string
+		if m[2] != -1 {
+			word = headline[m[2]:m[3]]
+		} else {
+			word = headline[m[4]:m[5]]
+		}
+		b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(headline[last:]))
+
+	return template.HTML(b.String())
+}
@@ -13,12 +13,18 @@ import (
 	"time"
 
 	"github.com/Masterminds/sprig"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/termora/berry/commands/admin/auditlog"
 	"github.com/termora/berry/common"
+	"github.com/termora/berry/common/httpcache"
 	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/common/staff"
 	"github.com/termora/berry/db"
 	"github.com/termora/berry/db/search/typesense"
+	"github.com/termora/berry/feeds"
 	"github.com/urfave/cli/v2"
 )
 
@@ -41,11 +47,41 @@ var Command = &cli.Command{
 	Aliases: []string{"web"},
 	Usage:   "Run the website",
 	Action:  run,
+
+	Subcommands: []*cli.Command{buildCommand},
 }
 
 type site struct {
 	db     *db.DB
 	Config common.SiteConfig
+	feeds  *feeds.Feeds
+
+	// pageSize is the number of terms on each page of paginated lists
+	pageSize int
+	// static is true when building a static copy of the site, see build.go
+	static bool
+
+	// auditLog logs changes made on the dashboard, it's nil in static builds
+	auditLog *auditlog.AuditLog
+	// state is used for the audit log channels and to tell pronoun submitters that their submission was resolved, nil if there's no bot token
+	state          *state.State
+	pronounChannel discord.ChannelID
+	// secureCookies is true if the site is served over HTTPS
+	secureCookies bool
+	// staff limits dashboard sessions to their user's current roles
+	staff *staff.Checker
+}
+
+func newSite(d *db.DB, c common.SiteConfig) *site {
+	s := &site{db: d, Config: c, pageSize: db.BrowsePageSize}
+
+	// feeds take the URL scheme separately
+	scheme, host := "https://", c.BaseURL
+	if i := strings.Index(host, "://"); i != -1 {
+		scheme, host = host[:i+3], host[i+3:]
+	}
+	s.feeds = feeds.New(d, scheme, host)
+	return s
 }
 
 // T ...
@@ -59,16 +95,81 @@ func (t *T) Render(w io.Writer, name string, data interface{}, c echo.Context) e
 }
 
 type renderData struct {
-	Conf  common.SiteConfig
-	Path  string
-	Dark  string
-	Tag   string
-	Tags  []string
-	Term  *db.Term
-	Terms []*db.Term
-	Query template.HTML
-	// Parsed markdown text for about pages
+	Conf     common.SiteConfig
+	Path     string
+	Dark     string
+	Tag      string
+	Tags     []db.Tag
+	Term     *db.Term
+	Terms    []*db.Term
+	Category *db.Category
+	Query    template.HTML
+	// Categories are shown in the navigation on every page
+	Categories []db.CategoryCount
+	// Letter is the letter being browsed, empty for all terms
+	Letter string
+	// Updated is the list of recently updated terms, Terms is recently added terms on the same page
+	Updated []*db.Term
+	// Page and Pages are set for paginated lists, PageURL is the URL that the page number is appended to
+	Page    int
+	Pages   int
+	PageURL string
+	// Title and Description are used for pages without a term, in the page title and OpenGraph metadata
+	Title       string
+	Description string
+
+	// Pronouns are the pronoun sets listed or shown on the page, PronounUsers is set when sorting by uses
+	Pronouns     []*db.PronounSet
+	PronounUsers map[int]int64
+	// Examples are the rendered example sentences, with Name used in place of the first form
+	Examples []string
+	Name     string
+	Language string
+	Sort     string
+	// Canonical is the page's canonical URL, if it has one
+	Canonical string
+	// Parsed markdown text for about pages and explanations
 	MD template.HTML
+
+	Explanation  *db.Explanation
+	Explanations []*db.Explanation
+
+	// SearchQuery is the unsanitized search query, for the search form
+	SearchQuery string
+	Filter      db.SearchFilter
+	FilterError string
+
+	// Revisions are a term's public changes, newest first
+	Revisions []db.Revision
+
+	// Theme is the embed theme, "light", "dark", or empty to follow the reader's preference
+	Theme string
+
+	// Static is true for pages in a static build, which can't use anything that needs the server
+	Static bool
+
+	// Session is the logged in session on dashboard pages, see dashboard.go
+	Session *db.DashboardSession
+	// LoginToken is the token from a dashboard login link, submitted to log in
+	LoginToken string
+	// LoginCSRF is submitted with the login form, and must match the login CSRF cookie
+	LoginCSRF string
+	// AllCategories are the categories that can be picked in the term editor
+	AllCategories []*db.Category
+	// Submissions are the pending pronoun submissions
+	Submissions []db.PronounSubmission
+	// Entries are audit log entries, NextEntries is the ID to continue from for older entries
+	Entries     []auditlog.Entry
+	NextEntries int64
+	// Subject is the audit log subject being shown, empty for all subjects
+	Subject string
+	// Files are the uploaded files, without their data
+	Files []db.File
+	// Embed is the term's embed in the bot, for the term editor's preview
+	Embed *discord.Embed
+	// Message and Error are shown at the top of dashboard pages, after a form is submitted
+	Message string
+	Error   string
 }
 
 func (r *renderData) parse(c echo.Context) renderData {
@@ -80,17 +181,90 @@ func (r *renderData) parse(c echo.Context) renderData {
 		r.Dark = ""
 	}
 
+	if cats, ok := c.Get("categories").([]db.CategoryCount); ok {
+		r.Categories = cats
+	}
+	r.Static, _ = c.Get("static").(bool)
+	r.Session, _ = c.Get("session").(*db.DashboardSession)
+
 	return *r
 }
 
-func run(ctx *cli.Context) error {
-	t := &T{
+func newRenderer() *T {
+	return &T{
 		templates: template.Must(template.New("").
 			Funcs(sprig.FuncMap()).
 			Funcs(funcMap()).
 			ParseFS(tmpls, "templates/*.html")),
 	}
+}
+
+// routes adds all of the site's routes to e
+func (s *site) routes(e *echo.Echo) {
+	e.GET("/static/*", echo.WrapHandler(
+		http.StripPrefix("/static/", http.FileServer(http.FS(mustSub(staticFS, "static")))),
+	))
+
+	e.GET("/dark", setDarkPreferences)
+
+	// only pages need the categories for the navigation, not files, feeds, or the dashboard
+	page := s.categoryNav
 
+	// pages that only change when terms do can be cached by browsers, the dark mode cookie changes the page too
+	cache := echo.WrapMiddleware(httpcache.Middleware(s.db, func(r *http.Request) string {
+		if cookie, err := r.Cookie("dark"); err == nil {
+			return cookie.Value
+		}
+		return ""
+	}))
+
+	e.GET("/", s.index, page)
+	e.GET("/term/:term", s.term, cache, page)
+	// history includes audit log reasons, which can change without the terms version changing
+	e.GET("/term/:term/history", s.termHistory, page)
+	e.GET("/tag/:tag", s.tag, cache, page)
+	e.GET("/category/:id", s.category, cache, page)
+	e.GET("/browse", s.browse, cache, page)
+	e.GET("/browse/:letter", s.browse, cache, page)
+	e.GET("/recent", s.recent, cache, page)
+	e.GET("/sitemap.xml", s.sitemap, cache)
+	// embeds don't use the dark mode cookie, as it isn't sent to iframes on other sites
+	e.GET("/embed/term/:term", s.embedTerm, echo.WrapMiddleware(httpcache.Middleware(s.db, func(r *http.Request) string {
+		return embedTheme(r.URL.Query().Get("theme"))
+	})))
+	e.GET("/embed.js", s.embedScript)
+	if !s.static {
+		e.GET("/oembed", s.oembed)
+		s.dashboardRoutes(e.Group("/admin"))
+	}
+	if s.static {
+		e.GET("/search", s.staticSearch, page)
+	} else {
+		// search results include explanations, which aren't part of the terms version, so they can't use cache
+		e.GET("/search", s.search, page)
+	}
+	e.GET("/explanations", s.explanations, page)
+	e.GET("/explanations/:name", s.explanation, page)
+	e.GET("/pronouns", s.pronounList, page)
+	e.GET("/pronouns/*", s.pronouns, page)
+	e.GET("/file/:id/:filename", s.file)
+	e.GET("/about/:page", s.staticPage, page)
+	e.GET("/feeds/:feed", s.feed)
+
+	e.GET("/robots.txt", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, `User-agent: *
+Disallow: /admin
+Disallow: /embed
+Disallow: /file
+Disallow: /oembed
+Disallow: /search
+Disallow: /static
+
+Sitemap: `+s.Config.BaseURL+`/sitemap.xml`)
+	})
+}
+
+func run(ctx *cli.Context) error {
 	c := common.ReadConfig()
 
 	d, err := db.Init(c.Core.DatabaseURL)
@@ -100,7 +274,7 @@ func run(ctx *cli.Context) error {
 	d.TermBaseURL = "/term/"
 	log.Info("Connected to database.")
 
-	// Typesense requires a bot running to sync terms
+	// only the bot keeps Typesense in sync, the site just searches it
 	if c.Core.TypesenseURL != "" && c.Core.TypesenseKey != "" {
 		d.Searcher, err = typesense.New(c.Core.TypesenseURL, c.Core.TypesenseKey, d.Pool)
 		if err != nil {
@@ -109,31 +283,27 @@ func run(ctx *cli.Context) error {
 		log.Info("Connected to Typesense server")
 	}
 
-	s := site{db: d, Config: c.Site}
+	s := newSite(d, c.Site)
+	s.auditLog = auditlog.NewStandalone(d, c)
+	s.state = s.auditLog.State
+	s.pronounChannel = c.Bot.PronounChannel
+	s.secureCookies = strings.HasPrefix(c.Site.BaseURL, "https://")
+	s.staff = staff.New(c)
+	if s.staff == nil {
+		log.Warn("No support server configured, dashboard sessions won't be checked against their user's roles")
+	}
+
+	d.OnChange(func(n db.Notification) {
+		if n.Table == db.TermsTable || n.Table == db.AuditLogTable || n.Action == db.ReconnectAction {
+			s.feeds.Invalidate()
+		}
+	})
+	d.Listen(ctx.Context)
 
 	e := echo.New()
-	e.Renderer = t
+	e.Renderer = newRenderer()
 	e.Use(middleware.Logger())
-
-	e.GET("/static/*", echo.WrapHandler(
-		http.StripPrefix("/static/", http.FileServer(http.FS(mustSub(staticFS, "static")))),
-	))
-
-	e.GET("/dark", setDarkPreferences)
-
-	e.GET("/", s.index)
-	e.GET("/term/:term", s.term)
-	e.GET("/tag/:tag", s.tag)
-	e.GET("/search", s.search)
-	e.GET("/file/:id/:filename", s.file)
-	e.GET("/about/:page", s.staticPage)
-
-	e.GET("/robots.txt", func(c echo.Context) error {
-		return c.String(http.StatusOK, `User-agent: *
-Disallow: /file
-Disallow: /search
-Disallow: /static`)
-	})
+	s.routes(e)
 
 	// get port
 	port := c.Site.Port
@@ -0,0 +1,97 @@
+package site
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/termora/berry/db/search"
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemap struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+func (s *site) sitemap(c echo.Context) (err error) {
+	base := s.Config.BaseURL
+	lastMod := func(t time.Time) string {
+		if t.IsZero() || t.Unix() == 0 {
+			return ""
+		}
+		return t.UTC().Format("2006-01-02")
+	}
+
+	// terms hidden from search aren't meant to be found, so they're left out
+	terms, err := s.db.CachedTerms(search.FlagSearchHidden)
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	tags, err := s.db.TagSlugs()
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	categories, err := s.db.GetCategories()
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	m := sitemap{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+
+	var latest time.Time
+	categoryMod := map[int]time.Time{}
+	for _, t := range terms {
+		if t.LastModified.After(latest) {
+			latest = t.LastModified
+		}
+		if t.LastModified.After(categoryMod[t.Category]) {
+			categoryMod[t.Category] = t.LastModified
+		}
+	}
+
+	m.URLs = append(m.URLs, sitemapURL{Loc: base + "/", LastMod: lastMod(latest)})
+	m.URLs = append(m.URLs, sitemapURL{Loc: base + "/pronouns"})
+
+	for _, t := range terms {
+		m.URLs = append(m.URLs, sitemapURL{Loc: base + termURL(t), LastMod: lastMod(t.LastModified)})
+	}
+
+	for _, t := range tags {
+		m.URLs = append(m.URLs, sitemapURL{Loc: base + "/tag/" + url.PathEscape(t.Slug), LastMod: lastMod(t.LastModified)})
+	}
+
+	for _, cat := range categories {
+		m.URLs = append(m.URLs, sitemapURL{Loc: base + "/category/" + strconv.Itoa(cat.ID), LastMod: lastMod(categoryMod[cat.ID])})
+	}
+
+	files, err := fs.ReadDir(staticPages, "static/pages")
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".md")
+		if f.IsDir() || name == f.Name() {
+			continue
+		}
+		m.URLs = append(m.URLs, sitemapURL{Loc: base + "/about/" + url.PathEscape(name)})
+	}
+
+	b, err := xml.Marshal(m)
+	if err != nil {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, append([]byte(xml.Header), b...))
+}
@@ -0,0 +1,46 @@
+// Live preview for the dashboard's term editor. Whenever the form changes, it's sent to the preview endpoint,
+// which returns the term as it would be shown in the bot and on its page.
+(function () {
+    "use strict";
+
+    var form = document.getElementById("term-editor");
+    var preview = document.getElementById("preview");
+    if (!form || !preview) {
+        return;
+    }
+
+    var timeout = null;
+    // only the latest request's response is shown, in case they finish out of order
+    var latest = 0;
+
+    function update() {
+        var n = ++latest;
+        fetch(form.getAttribute("data-preview"), {
+            method: "POST",
+            credentials: "same-origin",
+            body: new URLSearchParams(new FormData(form)),
+        }).then(function (resp) {
+            if (!resp.ok) {
+                throw new Error("Couldn't update the preview (" + resp.status + ").");
+            }
+            return resp.text();
+        }).then(function (html) {
+            if (n === latest) {
+                preview.innerHTML = html;
+            }
+        }, function (err) {
+            if (n === latest) {
+                preview.textContent = err.message;
+            }
+        });
+    }
+
+    function schedule() {
+        clearTimeout(timeout);
+        timeout = setTimeout(update, 300);
+    }
+
+    form.addEventListener("input", schedule);
+    form.addEventListener("change", schedule);
+    update();
+})();
@@ -0,0 +1,193 @@
+// Hover cards for term names on other sites. Include /embed.js and mark terms with
+// <span data-termora="term name or ID">, and the term is shown in a card when it's hovered over or focused.
+// The site serves this with a call to the function below, with the API's and the site's root URLs.
+// Without an API URL, terms are only linked to, not shown in cards.
+(function (api, site) {
+    "use strict";
+
+    var script = document.currentScript;
+    var theme = script ? script.getAttribute("data-theme") : null;
+
+    var style = document.createElement("style");
+    style.textContent =
+        ".termora-term { border-bottom: 1px dotted; cursor: help; }" +
+        ".termora-card { position: absolute; z-index: 10000; max-width: 350px; padding: 10px 12px; border-radius: 5px;" +
+        " font: 15px/1.5 sans-serif; box-shadow: 0 2px 8px rgba(0, 0, 0, 0.3); background: #fff; color: #444; text-align: left; }" +
+        ".termora-card a { color: #01b0f4; }" +
+        ".termora-card p { margin: 5px 0; }" +
+        ".termora-card small { opacity: 0.75; }" +
+        ".termora-card button { font: inherit; }" +
+        ".termora-dark { background: #36393f; color: #dcddde; }" +
+        (theme ? "" : "@media (prefers-color-scheme: dark) { .termora-card { background: #36393f; color: #dcddde; } }");
+    document.head.appendChild(style);
+
+    function termURL(name) {
+        return site + "/term/" + encodeURIComponent(name);
+    }
+
+    // plain returns markdown text with links and formatting removed
+    function plain(s) {
+        return s
+            .replace(/\[\[(.*?)(\|.*?)?\]\]/g, "$1")
+            .replace(/\[([^\]]*)\]\([^)]*\)/g, "$1")
+            .replace(/(\*\*|__|\|\||~~|\*|_)/g, "");
+    }
+
+    function shorten(s, n) {
+        if (s.length <= n) {
+            return s;
+        }
+        return s.slice(0, s.lastIndexOf(" ", n) > 0 ? s.lastIndexOf(" ", n) : n) + "...";
+    }
+
+    function el(tag, text) {
+        var e = document.createElement(tag);
+        if (text) {
+            e.textContent = text;
+        }
+        return e;
+    }
+
+    var terms = {};
+
+    function getTerm(name) {
+        if (!terms[name]) {
+            terms[name] = fetch(api + "/v1/term/" + encodeURIComponent(name)).then(function (resp) {
+                if (!resp.ok) {
+                    throw new Error(resp.status === 404 ? "Term not found." : "Couldn't get the term.");
+                }
+                return resp.json();
+            });
+            // don't cache errors, they might be rate limits
+            terms[name].catch(function () {
+                delete terms[name];
+            });
+        }
+        return terms[name];
+    }
+
+    function render(card, t) {
+        card.textContent = "";
+
+        var title = el("a", t.name);
+        title.href = termURL(t.id);
+        title.target = "_blank";
+        title.rel = "noopener";
+        var heading = el("strong");
+        heading.appendChild(title);
+        card.appendChild(heading);
+
+        if (t.aliases && t.aliases.length) {
+            card.appendChild(el("br"));
+            card.appendChild(el("small", "Aliases: " + t.aliases.join(", ")));
+        }
+
+        // flags & 4 is the warning flag
+        if (t.flags & 4) {
+            card.appendChild(el("p", "Warning: this term may be derogatory, exclusionary, or harmful. Use it with extreme caution."));
+        }
+
+        var desc = el("p", shorten(plain(t.description), 300));
+        if (t.content_warnings) {
+            card.appendChild(el("p", "Content warning: " + plain(t.content_warnings)));
+
+            var show = el("button", "Show description");
+            show.type = "button";
+            show.addEventListener("click", function () {
+                show.replaceWith(desc);
+            });
+            card.appendChild(show);
+        } else {
+            card.appendChild(desc);
+        }
+
+        card.appendChild(el("small", t.category));
+    }
+
+    function attach(span) {
+        var name = span.getAttribute("data-termora") || span.textContent.trim();
+        if (!name) {
+            return;
+        }
+
+        if (!api) {
+            var link = el("a");
+            link.href = termURL(name);
+            link.target = "_blank";
+            link.rel = "noopener";
+            while (span.firstChild) {
+                link.appendChild(span.firstChild);
+            }
+            span.appendChild(link);
+            return;
+        }
+
+        span.classList.add("termora-term");
+        span.tabIndex = 0;
+
+        var card = null;
+        var timeout = null;
+
+        function show() {
+            clearTimeout(timeout);
+            if (card) {
+                return;
+            }
+
+            card = el("div", "Loading...");
+            card.className = "termora-card" + (theme === "dark" ? " termora-dark" : "");
+            card.setAttribute("role", "tooltip");
+            card.addEventListener("mouseenter", show);
+            card.addEventListener("mouseleave", hide);
+            card.addEventListener("focusin", show);
+            card.addEventListener("focusout", hide);
+
+            var rect = span.getBoundingClientRect();
+            card.style.left = (rect.left + window.scrollX) + "px";
+            card.style.top = (rect.bottom + window.scrollY + 5) + "px";
+            document.body.appendChild(card);
+
+            var current = card;
+            getTerm(name).then(function (t) {
+                render(current, t);
+            }, function (err) {
+                current.textContent = err.message;
+            });
+        }
+
+        // hiding is delayed so the card can be moved to, to click its links
+        function hide() {
+            clearTimeout(timeout);
+            timeout = setTimeout(function () {
+                if (card) {
+                    card.remove();
+                    card = null;
+                }
+            }, 300);
+        }
+
+        span.addEventListener("mouseenter", show);
+        span.addEventListener("mouseleave", hide);
+        span.addEventListener("focus", show);
+        span.addEventListener("blur", hide);
+        span.addEventListener("keydown", function (e) {
+            if (e.key === "Escape" && card) {
+                card.remove();
+                card = null;
+            }
+        });
+    }
+
+    function init() {
+        var spans = document.querySelectorAll("span[data-termora]");
+        for (var i = 0; i < spans.length; i++) {
+            attach(spans[i]);
+        }
+    }
+
+    if (document.readyState === "loading") {
+        document.addEventListener("DOMContentLoaded", init);
+    } else {
+        init();
+    }
+})
@@ -0,0 +1,145 @@
+// Client-side search for static builds of the site, using the index written by `site build`.
+// Supports the same filters as the server's search page.
+(function () {
+    "use strict";
+
+    var params = new URLSearchParams(window.location.search);
+    var query = (params.get("q") || "").trim();
+    var filter = {
+        category: parseInt(params.get("category") || "0", 10) || 0,
+        exclude: params.getAll("exclude").join(",").split(",")
+            .map(function (t) { return t.trim().toLowerCase(); })
+            .filter(function (t) { return t !== ""; }),
+        noCW: params.get("no_cw") === "true",
+        sort: params.get("sort") || "relevance",
+    };
+
+    var form = document.querySelector("form.filters");
+    form.elements.q.value = query;
+    form.elements.category.value = filter.category ? String(filter.category) : "";
+    form.elements.sort.value = filter.sort;
+    form.elements.exclude.value = filter.exclude.join(",");
+    form.elements.no_cw.checked = filter.noCW;
+
+    var status = document.getElementById("status");
+    var results = document.getElementById("results");
+
+    if (query === "") {
+        status.textContent = "You did not input a query.";
+        return;
+    }
+
+    function escapeHTML(s) {
+        var div = document.createElement("div");
+        div.textContent = s;
+        return div.innerHTML;
+    }
+
+    function escapeRegExp(s) {
+        return s.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+    }
+
+    var words = query.toLowerCase().split(/\s+/).filter(function (w) { return w !== ""; });
+
+    // score is higher for better matches, 0 if the term doesn't match every word
+    function score(t) {
+        var name = t.name.toLowerCase();
+        var aliases = (t.aliases || []).join(" ").toLowerCase();
+        var desc = t.description.toLowerCase();
+
+        if (name === query.toLowerCase()) {
+            return 1000;
+        }
+
+        var total = 0;
+        for (var i = 0; i < words.length; i++) {
+            var w = words[i];
+            if (name.indexOf(w) === 0) {
+                total += 20;
+            } else if (name.indexOf(w) !== -1) {
+                total += 10;
+            } else if (aliases.indexOf(w) !== -1) {
+                total += 5;
+            } else if (desc.indexOf(w) !== -1) {
+                total += 1;
+            } else {
+                return 0;
+            }
+        }
+        return total;
+    }
+
+    // headline returns the part of the description around the first match, with matched words in <mark>
+    function headline(desc) {
+        var lower = desc.toLowerCase();
+        var start = 0;
+        for (var i = 0; i < words.length; i++) {
+            var pos = lower.indexOf(words[i]);
+            if (pos !== -1) {
+                start = Math.max(0, pos - 60);
+                break;
+            }
+        }
+
+        // split on the matched words, every odd part is a match
+        var re = new RegExp("(" + words.map(escapeRegExp).join("|") + ")", "gi");
+        var html = desc.slice(start, start + 250).split(re).map(function (part, i) {
+            return i % 2 === 1 ? "<mark>" + escapeHTML(part) + "</mark>" : escapeHTML(part);
+        }).join("");
+
+        return (start > 0 ? "..." : "") + html + (start + 250 < desc.length ? "..." : "");
+    }
+
+    status.textContent = "Searching...";
+
+    fetch("/search-index.json").then(function (resp) {
+        return resp.json();
+    }).then(function (terms) {
+        var matches = [];
+        terms.forEach(function (t) {
+            if (filter.category && t.category !== filter.category) {
+                return;
+            }
+            if (filter.noCW && t.cw) {
+                return;
+            }
+            if ((t.tags || []).some(function (tag) { return filter.exclude.indexOf(tag) !== -1; })) {
+                return;
+            }
+
+            var s = score(t);
+            if (s > 0) {
+                matches.push({ term: t, score: s });
+            }
+        });
+
+        matches.sort(function (a, b) {
+            switch (filter.sort) {
+                case "name":
+                    return a.term.name.toLowerCase().localeCompare(b.term.name.toLowerCase());
+                case "newest":
+                    return new Date(b.term.created) - new Date(a.term.created);
+                case "updated":
+                    return new Date(b.term.last_modified) - new Date(a.term.last_modified);
+                default:
+                    return b.score - a.score;
+            }
+        });
+        matches = matches.slice(0, 50);
+
+        if (matches.length === 0) {
+            status.textContent = "No results were found. Try searching for something else?";
+            return;
+        }
+        status.textContent = matches.length + (matches.length === 1 ? " result" : " results");
+
+        results.innerHTML = matches.map(function (m) {
+            var t = m.term;
+            return "<p><b><a href=\"" + escapeHTML(t.url) + "\">" + escapeHTML(t.name) + "</a></b><br />" +
+                "<small>" + escapeHTML(t.category_name) + (t.cw ? " &middot; has content warnings" : "") + "</small><br />" +
+                headline(t.description) + "</p>";
+        }).join("");
+    }).catch(function (err) {
+        status.textContent = "Couldn't load the search index: " + err;
+    });
+})();
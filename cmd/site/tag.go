@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 	"github.com/termora/berry/db"
 )
 
@@ -16,17 +18,50 @@ func (s *site) tag(c echo.Context) (err error) {
 
 	var terms []*db.Term
 	if tag == "untagged" || tag == "" {
+		tag = "untagged"
 		terms, err = s.db.UntaggedTerms()
-	} else {
-		terms, err = s.db.TagTerms(tag)
+		if err != nil {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+
+		return c.Render(http.StatusOK, "terms.html", (&renderData{
+			Conf:      s.Config,
+			Tag:       tag,
+			Terms:     terms,
+			Canonical: s.Config.BaseURL + "/tag/untagged",
+		}).parse(c))
 	}
+
+	t, canonical, err := s.db.TagBySlug(tag)
+	if err != nil {
+		if errors.Cause(err) != pgx.ErrNoRows {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+
+		// old links used the tag's name, so redirect those to the slug
+		t, err = s.db.TagByName(tag)
+		if err != nil {
+			if errors.Cause(err) != pgx.ErrNoRows {
+				return c.NoContent(http.StatusInternalServerError)
+			}
+			return c.Render(http.StatusNotFound, "404.html", (&renderData{
+				Conf: s.Config,
+			}).parse(c))
+		}
+	}
+	if !canonical {
+		return c.Redirect(http.StatusMovedPermanently, "/tag/"+url.PathEscape(t.Slug))
+	}
+
+	terms, err = s.db.TagTerms(t.Normalized)
 	if err != nil {
 		return c.NoContent(http.StatusInternalServerError)
 	}
 
 	return c.Render(http.StatusOK, "terms.html", (&renderData{
-		Conf:  s.Config,
-		Tag:   tag,
-		Terms: terms,
+		Conf:      s.Config,
+		Tag:       t.Name,
+		Terms:     terms,
+		Canonical: s.Config.BaseURL + "/tag/" + url.PathEscape(t.Slug),
 	}).parse(c))
 }
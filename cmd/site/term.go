@@ -7,56 +7,80 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 	"github.com/termora/berry/db"
 )
 
 var numberRegex = regexp.MustCompile(`^\d+$`)
 
 func (s *site) term(c echo.Context) (err error) {
-	var t *db.Term
-
-	name := c.Param("term")
-	name, err = url.PathUnescape(name)
+	t, canonical, err := s.lookupTerm(c.Param("term"))
 	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
-	}
-
-	if numberRegex.MatchString(name) {
-		id, _ := strconv.Atoi(name)
-
-		t, err = s.db.GetTerm(id)
-		if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
 			return c.NoContent(http.StatusNotFound)
 		}
-	} else {
-		terms, err := s.db.GetTerms(0)
-		if err != nil {
-			return c.NoContent(http.StatusInternalServerError)
-		}
-
-		for _, i := range terms {
-			if strings.EqualFold(i.Name, name) {
-				t = i
-				break
-			}
-		}
+		return c.NoContent(http.StatusInternalServerError)
 	}
 
-	if t == nil {
-		return c.NoContent(http.StatusNotFound)
+	if !canonical {
+		return c.Redirect(http.StatusMovedPermanently, termURL(t))
 	}
 
+	s.prepareTerm(t)
+
+	return c.Render(http.StatusOK, "term.html", (&renderData{
+		Conf:      s.Config,
+		Term:      t,
+		Canonical: s.Config.BaseURL + termURL(t),
+	}).parse(c))
+}
+
+// prepareTerm links other terms in t's text, and adds the disputed note, for showing it on a page
+func (s *site) prepareTerm(t *db.Term) {
 	t.Description = s.db.LinkTerms(t.Description)
 	t.Note = s.db.LinkTerms(t.Note)
 	if t.Disputed() {
 		t.Note = strings.TrimSpace(t.Note + "\n\n" + db.DisputedText)
 	}
-
 	t.ContentWarnings = s.db.LinkTerms(t.ContentWarnings)
+}
 
-	return c.Render(http.StatusOK, "term.html", (&renderData{
-		Conf: s.Config,
-		Term: t,
-	}).parse(c))
+// lookupTerm gets a term by its slug, ID, name, or alias, from an escaped path parameter.
+// canonical is false if the term wasn't found by its current slug, and should be redirected to it.
+// Returns pgx.ErrNoRows if no term matches.
+func (s *site) lookupTerm(param string) (t *db.Term, canonical bool, err error) {
+	name, err := url.PathUnescape(param)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.lookupTermName(name)
+}
+
+// lookupTermName is like lookupTerm, but takes an unescaped name
+func (s *site) lookupTermName(name string) (t *db.Term, canonical bool, err error) {
+	t, canonical, err = s.db.TermBySlug(name)
+	if err == nil || errors.Cause(err) != pgx.ErrNoRows {
+		return t, canonical, err
+	}
+
+	// not a slug, so it's either an ID, a name, or an alias
+	if numberRegex.MatchString(name) {
+		id, _ := strconv.Atoi(name)
+
+		t, err = s.db.CachedTerm(id)
+		return t, false, err
+	}
+
+	m, err := s.db.TermByName(name)
+	return m.Term, false, err
+}
+
+// termURL returns the path to a term's page, using its slug if it has one
+func termURL(t *db.Term) string {
+	if t.Slug == "" {
+		return "/term/" + strconv.Itoa(t.ID)
+	}
+	return "/term/" + url.PathEscape(t.Slug)
 }
@@ -11,19 +11,19 @@ import (
 func (bot *Bot) addPronouns(ctx *bcr.Context) (err error) {
 	i, skipped := 0, 0
 	for _, arg := range strings.Split(ctx.RawArgs, "\n") {
-		p := strings.Split(arg, "/")
-		if len(p) < 5 {
+		// sets in other languages are prefixed with the language code, such as "de: xier/xien/..."
+		lang := db.DefaultLanguage
+		if parts := strings.SplitN(arg, ":", 2); len(parts) == 2 {
+			lang, arg = strings.TrimSpace(parts[0]), parts[1]
+		}
+
+		p, err := db.NewPronounSet(lang, strings.Split(strings.TrimSpace(arg), "/"))
+		if err != nil {
 			skipped++
 			continue
 		}
 
-		_, err = bot.DB.AddPronoun(db.PronounSet{
-			Subjective: p[0],
-			Objective:  p[1],
-			PossDet:    p[2],
-			PossPro:    p[3],
-			Reflexive:  p[4],
-		})
+		_, err = bot.DB.AddPronoun(p)
 		if err != nil {
 			skipped++
 			continue
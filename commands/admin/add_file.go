@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
 )
 
 func (bot *Bot) upload(ctx *bcr.Context) (err error) {
@@ -18,12 +18,12 @@ func (bot *Bot) upload(ctx *bcr.Context) (err error) {
 
 	a := ctx.Message.Attachments[0]
 
-	if a.Size > 1*1024*1024 {
+	if a.Size > db.MaxFileSize {
 		_, err = ctx.Replyc(bcr.ColourRed, "The attachment is too big (%v (%v bytes) > 1 MB)", humanize.Bytes(a.Size), humanize.Comma(int64(a.Size)))
 		return
 	}
 
-	contentType := contentType(a.Filename)
+	contentType := db.ImageContentType(a.Filename)
 
 	if contentType == "unknown" {
 		_, err = ctx.Replyc(bcr.ColourRed, "The attachment you gave isn't an image.")
@@ -56,23 +56,3 @@ func (bot *Bot) upload(ctx *bcr.Context) (err error) {
 	_, err = ctx.Reply("File added with ID %v!%v", f.ID, link)
 	return
 }
-
-func contentType(filename string) string {
-	if bcr.HasAnySuffix(filename, ".jpg", ".jpeg") {
-		return "image/jpeg"
-	}
-
-	if strings.HasSuffix(filename, ".png") {
-		return "image/png"
-	}
-
-	if strings.HasSuffix(filename, ".gif") {
-		return "image/gif"
-	}
-
-	if strings.HasSuffix(filename, ".webp") {
-		return "image/webp"
-	}
-
-	return "unknown"
-}
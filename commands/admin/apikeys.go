@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
+)
+
+func (bot *Bot) createAPIKey(ctx *bcr.Context) (err error) {
+	name := strings.Join(ctx.Args, " ")
+
+	var rateLimit *int
+	if n, _ := ctx.Flags.GetInt("rate-limit"); n > 0 {
+		rateLimit = &n
+	}
+
+	scope := db.ScopeRead
+	if s, _ := ctx.Flags.GetString("scope"); s != "" {
+		scope = db.APIScope(strings.ToLower(s))
+		if !scope.Valid() {
+			_, err = ctx.Replyc(bcr.ColourRed, "Invalid scope, valid scopes are `read`, `director`, and `admin`.")
+			return
+		}
+	}
+
+	if len(name) > 100 {
+		_, err = ctx.Replyc(bcr.ColourRed, "Name too long, maximum 100 characters (%v characters)", len(name))
+		return
+	}
+
+	ch, err := ctx.State.CreatePrivateChannel(ctx.Author.ID)
+	if err != nil {
+		_, err = ctx.Send("There was an error opening a DM channel. Are you sure your DMs are open?")
+		return
+	}
+
+	key, token, err := bot.DB.CreateAPIKey(name, ctx.Author.ID, scope, rateLimit)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessage(ch.ID, fmt.Sprintf("API key **%v** (ID %v, scope `%v`):\n```%v```\nThis key will not be shown again!", key.Name, key.ID, key.Scope, token))
+	if err != nil {
+		// the key can't be shown anywhere else, so there's no point in keeping it
+		bot.DB.RevokeAPIKey(key.ID)
+		_, err = ctx.Send("There was an error sending you the key. Are you sure your DMs are open?")
+		return
+	}
+
+	_, err = ctx.Reply("Created API key %v with ID %v, check your DMs!", key.Name, key.ID)
+	return
+}
+
+func (bot *Bot) listAPIKeys(ctx *bcr.Context) (err error) {
+	keys, err := bot.DB.APIKeys()
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(keys) == 0 {
+		_, err = ctx.Reply("There are no API keys.")
+		return
+	}
+
+	var s []string
+	for _, k := range keys {
+		str := fmt.Sprintf("**%v** (%v, `%v...`)\nScope: %v\nCreated <t:%v:R>", k.Name, k.ID, k.Prefix, k.Scope, k.Created.Unix())
+		if k.CreatedBy.IsValid() {
+			str += " by " + k.CreatedBy.Mention()
+		}
+		if k.RateLimit != nil {
+			str += fmt.Sprintf("\nRate limit: %v/minute", *k.RateLimit)
+		}
+		if k.LastUsed != nil {
+			str += fmt.Sprintf("\nLast used <t:%v:R>", k.LastUsed.Unix())
+		}
+		if k.Revoked != nil {
+			str += fmt.Sprintf("\n**Revoked** <t:%v:R>", k.Revoked.Unix())
+		}
+
+		s = append(s, str+"\n\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("API keys", db.EmbedColour, s, 5),
+		5*time.Minute,
+	)
+	return
+}
+
+func (bot *Bot) revokeAPIKey(ctx *bcr.Context) (err error) {
+	id, err := strconv.Atoi(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "Couldn't parse %v as a key ID.", bcr.AsCode(ctx.Args[0]))
+		return
+	}
+
+	yes, timeout := ctx.ConfirmButton(ctx.Author.ID, bcr.ConfirmData{
+		Message: fmt.Sprintf("Are you sure you want to revoke API key %v? This can't be undone.", id),
+	})
+	if timeout {
+		return ctx.SendX("Timed out.")
+	}
+	if !yes {
+		return ctx.SendX("Cancelled.")
+	}
+
+	ok, err := bot.DB.RevokeAPIKey(id)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+	if !ok {
+		_, err = ctx.Replyc(bcr.ColourRed, "There's no active API key with that ID.")
+		return
+	}
+
+	_, err = ctx.Reply("Revoked API key %v.", id)
+	return
+}
+
+func (bot *Bot) apiKeyUsage(ctx *bcr.Context) (err error) {
+	id, err := strconv.Atoi(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "Couldn't parse %v as a key ID.", bcr.AsCode(ctx.Args[0]))
+		return
+	}
+
+	usage, err := bot.DB.APIKeyUsage(id, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(usage) == 0 {
+		_, err = ctx.Reply("That key hasn't been used in the last 30 days.")
+		return
+	}
+
+	var (
+		total      int64
+		byEndpoint = map[string]int64{}
+		endpoints  []string
+	)
+	for _, u := range usage {
+		total += u.Count
+		if _, ok := byEndpoint[u.Endpoint]; !ok {
+			endpoints = append(endpoints, u.Endpoint)
+		}
+		byEndpoint[u.Endpoint] += u.Count
+	}
+
+	var s string
+	for _, e := range endpoints {
+		s += fmt.Sprintf("`%v`: %v\n", e, byEndpoint[e])
+	}
+
+	_, err = ctx.Send("", discord.Embed{
+		Title:       fmt.Sprintf("Usage for API key %v", id),
+		Description: s,
+		Color:       db.EmbedColour,
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("%v requests in the last 30 days", total),
+		},
+	})
+	return
+}
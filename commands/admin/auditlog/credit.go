@@ -0,0 +1,29 @@
+package auditlog
+
+import (
+	"strings"
+
+	"github.com/starshine-sys/bcr"
+)
+
+// credit sets whether the author is credited by name on public term history pages.
+// Without this, their changes are shown without any user information.
+func (bot *AuditLog) credit(ctx *bcr.Context) (err error) {
+	switch strings.ToLower(ctx.Args[0]) {
+	case "on", "yes", "true", "enable":
+		err = bot.DB.SetHistoryCredit(ctx.Author.ID, ctx.Author.Username, true)
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+		_, err = ctx.Replyc(bcr.ColourGreen, "Your changes will now be credited to **%v** in public term history. Run this command again to update your name.", ctx.Author.Username)
+	case "off", "no", "false", "disable":
+		err = bot.DB.SetHistoryCredit(ctx.Author.ID, "", false)
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+		_, err = ctx.Replyc(bcr.ColourGreen, "Your changes will no longer be credited in public term history.")
+	default:
+		_, err = ctx.Replyc(bcr.ColourRed, "Please choose either `on` or `off`.")
+	}
+	return
+}
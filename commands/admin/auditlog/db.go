@@ -13,6 +13,7 @@ import (
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
+	"github.com/termora/berry/webhooks"
 )
 
 // EntrySubject ...
@@ -56,6 +57,8 @@ type Entry struct {
 
 	UserID discord.UserID
 	Reason sql.NullString
+	// ReasonPrivate is true if the reason is only shown in the private log
+	ReasonPrivate bool
 
 	Timestamp time.Time
 
@@ -197,9 +200,10 @@ func (bot *AuditLog) desc(entry Entry) string {
 			log.Errorf("Error unmarshaling term: %v", err)
 		}
 
+		// the config is used instead of the database's base URL, which is relative on the website
 		desc += " term **"
-		if entry.Action != DeleteAction && bot.DB.TermBaseURL != "" {
-			desc += fmt.Sprintf("[%v](%v%v)", term.Name, bot.DB.TermBaseURL, term.ID)
+		if base := bot.Config.Bot.TermBaseURL(); entry.Action != DeleteAction && base != "" {
+			desc += fmt.Sprintf("[%v](%v%v)", term.Name, base, term.ID)
 		} else {
 			desc += term.Name
 		}
@@ -223,6 +227,19 @@ func (bot *AuditLog) SendLog(subjectID int, subjectType EntrySubject, actionType
 		return 0, err
 	}
 
+	if bot.Webhooks != nil {
+		bot.Webhooks.Send(webhooks.Event{
+			Event:      webhooks.EventName(string(entry.Subject), string(entry.Action)),
+			Timestamp:  entry.Timestamp,
+			Subject:    string(entry.Subject),
+			SubjectID:  entry.SubjectID,
+			Action:     string(entry.Action),
+			AuditLogID: entry.ID,
+			Before:     entry.Before,
+			After:      entry.After,
+		})
+	}
+
 	desc := bot.desc(entry)
 	publicID, err := bot.sendPublicEmbed(entry, desc)
 	if err != nil {
@@ -237,12 +254,33 @@ func (bot *AuditLog) SendLog(subjectID int, subjectType EntrySubject, actionType
 	return entry.ID, err
 }
 
-func (bot *AuditLog) updateReason(id int64, reason string) (e Entry, err error) {
+// LogChange adds an entry for a change made outside of the bot, such as through the API or the dashboard.
+// The change has already been made at this point, so errors are only logged.
+func (bot *AuditLog) LogChange(subjectID int, subjectType EntrySubject, actionType ActionType, before, after interface{}, userID discord.UserID, reason *string) {
+	// so the change shows up right away instead of after the next version check
+	bot.DB.InvalidateTerms()
+
+	_, err := bot.SendLog(subjectID, subjectType, actionType, before, after, userID, reason)
+	if err != nil {
+		log.Errorf("Error sending audit log for %v %v: %v", subjectType, subjectID, err)
+	}
+}
+
+func (bot *AuditLog) updateReason(id int64, reason string, private bool) (e Entry, err error) {
 	s := sql.NullString{
 		Valid:  true,
 		String: reason,
 	}
 
-	err = pgxscan.Get(context.Background(), bot.DB.Pool, &e, "update audit_log set reason = $1 where id = $2 returning *", s, id)
+	err = pgxscan.Get(context.Background(), bot.DB.Pool, &e, "update audit_log set reason = $1, reason_private = $2 where id = $3 returning *", s, private, id)
+	return
+}
+
+// Entries returns up to limit entries with IDs lower than beforeID (or the newest entries, if beforeID is 0), newest first.
+// If subject is set, only entries for that subject are returned.
+func (bot *AuditLog) Entries(subject EntrySubject, beforeID int64, limit int) (es []Entry, err error) {
+	err = pgxscan.Select(context.Background(), bot.DB.Pool, &es, `select * from audit_log
+	where ($1::text = '' or subject::text = $1) and ($2::bigint = 0 or id < $2)
+	order by id desc limit $3`, string(subject), beforeID, limit)
 	return
 }
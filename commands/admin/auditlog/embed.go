@@ -9,7 +9,7 @@ import (
 )
 
 func (bot *AuditLog) sendPublicEmbed(e Entry, description string) (id discord.MessageID, err error) {
-	if !bot.Config.Bot.AuditLogPublic.IsValid() {
+	if bot.State == nil || !bot.Config.Bot.AuditLogPublic.IsValid() {
 		return
 	}
 
@@ -30,7 +30,7 @@ func (bot *AuditLog) publicEmbed(e Entry, description string) discord.Embed {
 		Timestamp: discord.NewTimestamp(e.Timestamp),
 	}
 
-	if e.Reason.Valid {
+	if e.Reason.Valid && !e.ReasonPrivate {
 		embed.Description += ". Reason: " + e.Reason.String
 	}
 
@@ -47,7 +47,7 @@ func (bot *AuditLog) publicEmbed(e Entry, description string) discord.Embed {
 }
 
 func (bot *AuditLog) sendPrivateEmbed(e Entry) (id discord.MessageID, err error) {
-	if !bot.Config.Bot.AuditLogPrivate.IsValid() {
+	if bot.State == nil || !bot.Config.Bot.AuditLogPrivate.IsValid() {
 		return
 	}
 
@@ -4,12 +4,21 @@ import (
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/bot"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/webhooks"
 )
 
 // AuditLog ...
 type AuditLog struct {
-	State *state.State
+	State  *state.State
+	DB     *db.DB
+	Config common.Config
 
+	// Webhooks is sent every entry, if it's not nil
+	Webhooks *webhooks.Dispatcher
+
+	// Bot is nil if the audit log is used outside of the bot
 	*bot.Bot
 }
 
@@ -18,8 +27,28 @@ func New(bot *bot.Bot) *AuditLog {
 	st, _ := bot.Router.StateFromGuildID(0)
 
 	return &AuditLog{
-		State: st,
-		Bot:   bot,
+		State:    st,
+		DB:       bot.DB,
+		Config:   bot.Config,
+		Webhooks: bot.Webhooks,
+		Bot:      bot,
+	}
+}
+
+// NewStandalone returns an AuditLog for use outside of the bot, such as in the API or on the website.
+// If no bot token is configured, State is nil and entries are only saved to the database, not sent to the log channels.
+func NewStandalone(db *db.DB, config common.Config) *AuditLog {
+	// the audit log only needs the REST API, so the gateway is never opened
+	var st *state.State
+	if config.Bot.Token != "" {
+		st = state.New("Bot " + config.Bot.Token)
+	}
+
+	return &AuditLog{
+		State:    st,
+		DB:       db,
+		Config:   config,
+		Webhooks: webhooks.New(db),
 	}
 }
 
@@ -52,5 +81,23 @@ func Init(bot *bot.Bot, perms bcr.CustomPerms) (s string, list []*bcr.Command) {
 		Command:           b.reason,
 	})
 
+	cmd.AddSubcommand(&bcr.Command{
+		Name:              "privatereason",
+		Summary:           "Set a reason for the given audit log entry, only shown in the private log.",
+		Usage:             "<ID|latest> <reason...>",
+		Args:              bcr.MinArgs(2),
+		CustomPermissions: perms,
+		Command:           b.privateReason,
+	})
+
+	cmd.AddSubcommand(&bcr.Command{
+		Name:              "credit",
+		Summary:           "Choose whether your name is shown on public term history pages.",
+		Usage:             "<on|off>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: perms,
+		Command:           b.credit,
+	})
+
 	return
 }
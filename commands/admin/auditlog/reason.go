@@ -11,6 +11,15 @@ import (
 )
 
 func (bot *AuditLog) reason(ctx *bcr.Context) (err error) {
+	return bot.setReason(ctx, false)
+}
+
+// privateReason sets a reason that's only shown in the private log, not in the public log or term history
+func (bot *AuditLog) privateReason(ctx *bcr.Context) (err error) {
+	return bot.setReason(ctx, true)
+}
+
+func (bot *AuditLog) setReason(ctx *bcr.Context, private bool) (err error) {
 	reason := strings.TrimSpace(strings.TrimPrefix(ctx.RawArgs, ctx.Args[0]))
 	if reason == ctx.RawArgs {
 		reason = strings.Join(ctx.Args[1:], " ")
@@ -41,7 +50,7 @@ func (bot *AuditLog) reason(ctx *bcr.Context) (err error) {
 		}
 	}
 
-	entry, err := bot.updateReason(id, reason)
+	entry, err := bot.updateReason(id, reason, private)
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
@@ -59,6 +68,10 @@ func (bot *AuditLog) reason(ctx *bcr.Context) (err error) {
 		}
 	}
 
+	if private {
+		_, err = ctx.Replyc(bcr.ColourGreen, "Updated reason for entry %v! It's only shown in the private log.", entry.ID)
+		return
+	}
 	_, err = ctx.Replyc(bcr.ColourGreen, "Updated reason for entry %v!", entry.ID)
 	return
 }
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
+)
+
+func (bot *Bot) dashboard(ctx *bcr.Context) (err error) {
+	if bot.Config.Bot.Website == "" {
+		_, err = ctx.Replyc(bcr.ColourRed, "There's no website configured, so the dashboard can't be used.")
+		return
+	}
+
+	// the session can do whatever the user can do right now, admins can also delete terms
+	scope := db.ScopeDirector
+	if bot.IsAdmin(ctx.Member) {
+		scope = db.ScopeAdmin
+	}
+
+	ch, err := ctx.State.CreatePrivateChannel(ctx.Author.ID)
+	if err != nil {
+		_, err = ctx.Send("There was an error opening a DM channel. Are you sure your DMs are open?")
+		return
+	}
+
+	token, err := bot.DB.CreateDashboardLogin(ctx.Author.ID, ctx.Author.Tag(), scope)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessage(ch.ID, fmt.Sprintf(
+		"Use this link to log in to the dashboard:\n<%vadmin/login?token=%v>\nThe link can only be used once, and expires in %v minutes. Don't share it with anyone!",
+		bot.Config.Bot.Website, token, int(db.DashboardLoginExpiry.Minutes()),
+	))
+	if err != nil {
+		_, err = ctx.Send("There was an error sending you the link. Are you sure your DMs are open?")
+		return
+	}
+
+	_, err = ctx.Reply("Check your DMs for a login link!")
+	return
+}
@@ -99,8 +99,8 @@ For ` + "`aliases`" + ` and ` + "`tags`" + `, you can use "-clear", with no quot
 
 func (bot *Bot) editTermTitle(ctx *bcr.Context, t *db.Term) (err error) {
 	title := strings.Join(ctx.Args[2:], " ")
-	if len(title) > 200 {
-		_, err = ctx.Sendf("Title too long (%v > 200).", len(title))
+	if len(title) > db.MaxTermNameLength {
+		_, err = ctx.Sendf("Title too long (%v > %v).", len(title), db.MaxTermNameLength)
 		return
 	}
 
@@ -127,8 +127,8 @@ func (bot *Bot) editTermTitle(ctx *bcr.Context, t *db.Term) (err error) {
 
 func (bot *Bot) editTermDesc(ctx *bcr.Context, t *db.Term) (err error) {
 	desc := strings.Join(ctx.Args[2:], " ")
-	if len(desc) > 1800 {
-		_, err = ctx.Sendf("Description too long (%v > 1800).", len(desc))
+	if len(desc) > db.MaxDescriptionLength {
+		_, err = ctx.Sendf("Description too long (%v > %v).", len(desc), db.MaxDescriptionLength)
 		return
 	}
 
@@ -155,8 +155,8 @@ func (bot *Bot) editTermDesc(ctx *bcr.Context, t *db.Term) (err error) {
 
 func (bot *Bot) editTermSource(ctx *bcr.Context, t *db.Term) (err error) {
 	source := strings.Join(ctx.Args[2:], " ")
-	if len(source) > 200 {
-		_, err = ctx.Sendf("Source too long (%v > 200).", len(source))
+	if len(source) > db.MaxSourceLength {
+		_, err = ctx.Sendf("Source too long (%v > %v).", len(source), db.MaxSourceLength)
 		return
 	}
 
@@ -187,8 +187,8 @@ func (bot *Bot) editTermAliases(ctx *bcr.Context, t *db.Term) (err error) {
 		aliases = ctx.Args[2:]
 	}
 
-	if len(strings.Join(aliases, ", ")) > 1000 {
-		_, err = ctx.Sendf("Total length of aliases too long (%v > 1000)", len(strings.Join(aliases, ", ")))
+	if len(strings.Join(aliases, ", ")) > db.MaxAliasesLength {
+		_, err = ctx.Sendf("Total length of aliases too long (%v > %v)", len(strings.Join(aliases, ", ")), db.MaxAliasesLength)
 		return
 	}
 
@@ -102,10 +102,11 @@ func Init(b *bot.Bot) (m string, out []*bcr.Command) {
 	})
 
 	a.AddSubcommand(&bcr.Command{
-		Name:    "add-pronouns",
-		Aliases: []string{"addpronouns"},
-		Summary: "Add a pronoun set",
-		Usage:   "<subjective>/<objective>/<poss. determiner>/<poss. pronoun>/<reflexive>",
+		Name:        "add-pronouns",
+		Aliases:     []string{"addpronouns"},
+		Summary:     "Add a pronoun set",
+		Description: "Add one set per line. Sets in languages other than English are prefixed with the language code, for example `de: xier/xien/xiem/xieser/xies`.",
+		Usage:       "<subjective>/<objective>/<poss. determiner>/<poss. pronoun>/<reflexive>",
 
 		CustomPermissions: directors,
 		Command:           bot.addPronouns,
@@ -274,6 +275,129 @@ func Init(b *bot.Bot) (m string, out []*bcr.Command) {
 		Command:   bot.allContributors,
 	})
 
+	keys := a.AddSubcommand(&bcr.Command{
+		Name:              "apikey",
+		Aliases:           []string{"api-key", "apikeys"},
+		Summary:           "Manage API keys",
+		CustomPermissions: directors,
+		Command: func(ctx *bcr.Context) (err error) {
+			return ctx.Help([]string{"admin", "apikey"})
+		},
+	})
+
+	keys.AddSubcommand(&bcr.Command{
+		Name:    "create",
+		Summary: "Create an API key, the key is sent in DMs",
+		Usage:   "<name>",
+		Args:    bcr.MinArgs(1),
+		Flags: func(fs *pflag.FlagSet) *pflag.FlagSet {
+			fs.IntP("rate-limit", "r", 0, "Requests per minute (0 for the default)")
+			fs.StringP("scope", "s", "read", "What the key can do (read, director, or admin)")
+			return fs
+		},
+		CustomPermissions: admins,
+		Command:           bot.createAPIKey,
+	})
+
+	keys.AddSubcommand(&bcr.Command{
+		Name:              "list",
+		Summary:           "List all API keys",
+		CustomPermissions: directors,
+		Command:           bot.listAPIKeys,
+	})
+
+	keys.AddSubcommand(&bcr.Command{
+		Name:              "revoke",
+		Summary:           "Revoke an API key",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.revokeAPIKey,
+	})
+
+	keys.AddSubcommand(&bcr.Command{
+		Name:              "usage",
+		Summary:           "Show an API key's usage in the last 30 days",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: directors,
+		Command:           bot.apiKeyUsage,
+	})
+
+	a.AddSubcommand(&bcr.Command{
+		Name:              "dashboard",
+		Aliases:           []string{"web", "login"},
+		Summary:           "Get a link to log in to the web dashboard, the link is sent in DMs",
+		CustomPermissions: directors,
+		Command:           bot.dashboard,
+	})
+
+	hooks := a.AddSubcommand(&bcr.Command{
+		Name:              "webhook",
+		Aliases:           []string{"webhooks"},
+		Summary:           "Manage outgoing webhooks",
+		CustomPermissions: admins,
+		Command: func(ctx *bcr.Context) (err error) {
+			return ctx.Help([]string{"admin", "webhook"})
+		},
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "add",
+		Aliases:           []string{"create"},
+		Summary:           "Add a webhook, the secret is sent in DMs",
+		Description:       "Events are `<subject>.<action>`, `<subject>.*`, or `*` (the default). Subjects are `term`, `pronouns`, and `explanation`, actions are `create`, `update`, and `delete`.",
+		Usage:             "<url> [events...]",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.addWebhook,
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "list",
+		Summary:           "List all webhooks",
+		CustomPermissions: admins,
+		Command:           bot.listWebhooks,
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "remove",
+		Aliases:           []string{"delete"},
+		Summary:           "Remove a webhook",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.removeWebhook,
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "enable",
+		Summary:           "Re-enable a disabled webhook",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.enableWebhook,
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "disable",
+		Summary:           "Disable a webhook",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.disableWebhook,
+	})
+
+	hooks.AddSubcommand(&bcr.Command{
+		Name:              "deliveries",
+		Aliases:           []string{"log"},
+		Summary:           "Show a webhook's latest delivery attempts",
+		Usage:             "<id>",
+		Args:              bcr.MinArgs(1),
+		CustomPermissions: admins,
+		Command:           bot.webhookDeliveries,
+	})
+
 	i := bot.Router.AddCommand(bot.Router.AliasMust("ai", nil, []string{"admin", "import"}, nil))
 	i.Args = bcr.MinArgs(1)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
 )
 
 func (bot *Bot) setCW(ctx *bcr.Context) (err error) {
@@ -32,8 +33,8 @@ func (bot *Bot) setCW(ctx *bcr.Context) (err error) {
 	}
 
 	// if it's too long, return
-	if len(cw) > 1000 {
-		_, err = ctx.Sendf("❌ The CW you gave is too long (%v > 1000 characters).", len(cw))
+	if len(cw) > db.MaxCWLength {
+		_, err = ctx.Sendf("❌ The CW you gave is too long (%v > %v characters).", len(cw), db.MaxCWLength)
 		return
 	}
 
@@ -48,8 +48,8 @@ func (bot *Bot) setNote(ctx *bcr.Context) (err error) {
 		note = n
 	}
 
-	if len(note) > 1000 {
-		_, err = ctx.Sendf("❌ The note you gave is too long (%v > 1000 characters).", len(note))
+	if len(note) > db.MaxNoteLength {
+		_, err = ctx.Sendf("❌ The note you gave is too long (%v > %v characters).", len(note), db.MaxNoteLength)
 		return
 	}
 
@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
+	"github.com/termora/berry/webhooks"
+)
+
+func (bot *Bot) addWebhook(ctx *bcr.Context) (err error) {
+	u, err := url.Parse(ctx.Args[0])
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		_, err = ctx.Replyc(bcr.ColourRed, "%v isn't a valid URL.", bcr.AsCode(ctx.Args[0]))
+		return
+	}
+
+	events := []string{"*"}
+	if len(ctx.Args) > 1 {
+		events = nil
+		for _, e := range ctx.Args[1:] {
+			e = strings.ToLower(strings.Trim(e, ","))
+			if !webhooks.ValidFilter(e) {
+				_, err = ctx.Replyc(bcr.ColourRed, "%v isn't a valid event.", bcr.AsCode(e))
+				return
+			}
+			events = append(events, e)
+		}
+	}
+
+	ch, err := ctx.State.CreatePrivateChannel(ctx.Author.ID)
+	if err != nil {
+		_, err = ctx.Send("There was an error opening a DM channel. Are you sure your DMs are open?")
+		return
+	}
+
+	w, err := bot.DB.CreateWebhook(u.String(), events, ctx.Author.ID)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.State.SendMessage(ch.ID, fmt.Sprintf("Secret for webhook %v (<%v>):\n```%v```\nThis secret will not be shown again!", w.ID, w.URL, w.Secret))
+	if err != nil {
+		// the secret can't be shown anywhere else, so the webhook is useless without it
+		bot.DB.RemoveWebhook(w.ID)
+		_, err = ctx.Send("There was an error sending you the secret. Are you sure your DMs are open?")
+		return
+	}
+
+	_, err = ctx.Reply("Added webhook %v for events %v, check your DMs for its secret!", w.ID, bcr.AsCode(strings.Join(w.Events, ", ")))
+	return
+}
+
+func (bot *Bot) listWebhooks(ctx *bcr.Context) (err error) {
+	hooks, err := bot.DB.Webhooks()
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(hooks) == 0 {
+		_, err = ctx.Reply("There are no webhooks.")
+		return
+	}
+
+	var s []string
+	for _, w := range hooks {
+		str := fmt.Sprintf("**%v**: <%v>\nEvents: `%v`\nCreated <t:%v:R>", w.ID, w.URL, strings.Join(w.Events, ", "), w.Created.Unix())
+		if w.CreatedBy.IsValid() {
+			str += " by " + w.CreatedBy.Mention()
+		}
+		if w.Failures > 0 {
+			str += fmt.Sprintf("\nFailed deliveries in a row: %v", w.Failures)
+		}
+		if w.Disabled != nil {
+			str += fmt.Sprintf("\n**Disabled** <t:%v:R>", w.Disabled.Unix())
+			if w.DisabledReason != nil {
+				str += ": " + *w.DisabledReason
+			}
+		}
+
+		s = append(s, str+"\n\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator("Webhooks", db.EmbedColour, s, 5),
+		5*time.Minute,
+	)
+	return
+}
+
+func (bot *Bot) webhookArg(ctx *bcr.Context) (w db.Webhook, ok bool, err error) {
+	id, err := strconv.Atoi(ctx.Args[0])
+	if err != nil {
+		_, err = ctx.Replyc(bcr.ColourRed, "Couldn't parse %v as a webhook ID.", bcr.AsCode(ctx.Args[0]))
+		return w, false, err
+	}
+
+	w, err = bot.DB.Webhook(id)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			_, err = ctx.Replyc(bcr.ColourRed, "There's no webhook with that ID.")
+			return w, false, err
+		}
+		return w, false, bot.DB.InternalError(ctx, err)
+	}
+	return w, true, nil
+}
+
+func (bot *Bot) removeWebhook(ctx *bcr.Context) (err error) {
+	w, ok, err := bot.webhookArg(ctx)
+	if !ok {
+		return err
+	}
+
+	yes, timeout := ctx.ConfirmButton(ctx.Author.ID, bcr.ConfirmData{
+		Message: fmt.Sprintf("Are you sure you want to remove webhook %v (<%v>)? This can't be undone.", w.ID, w.URL),
+	})
+	if timeout {
+		return ctx.SendX("Timed out.")
+	}
+	if !yes {
+		return ctx.SendX("Cancelled.")
+	}
+
+	err = bot.DB.RemoveWebhook(w.ID)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.Reply("Removed webhook %v.", w.ID)
+	return
+}
+
+func (bot *Bot) enableWebhook(ctx *bcr.Context) (err error) {
+	w, ok, err := bot.webhookArg(ctx)
+	if !ok {
+		return err
+	}
+
+	err = bot.DB.EnableWebhook(w.ID)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.Reply("Enabled webhook %v.", w.ID)
+	return
+}
+
+func (bot *Bot) disableWebhook(ctx *bcr.Context) (err error) {
+	w, ok, err := bot.webhookArg(ctx)
+	if !ok {
+		return err
+	}
+
+	err = bot.DB.DisableWebhook(w.ID, "Disabled by "+ctx.Author.Tag())
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.Reply("Disabled webhook %v.", w.ID)
+	return
+}
+
+func (bot *Bot) webhookDeliveries(ctx *bcr.Context) (err error) {
+	w, ok, err := bot.webhookArg(ctx)
+	if !ok {
+		return err
+	}
+
+	deliveries, err := bot.DB.WebhookDeliveries(w.ID, 50)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(deliveries) == 0 {
+		_, err = ctx.Reply("There are no deliveries for that webhook.")
+		return
+	}
+
+	var s []string
+	for _, d := range deliveries {
+		if d.NextAttempt != nil {
+			s = append(s, fmt.Sprintf("`%v` (attempt %v)\n⏳ Pending, <t:%v:R>\nDelivery `%v`\n\n", d.Event, d.Attempt, d.NextAttempt.Unix(), d.DeliveryID))
+			continue
+		}
+
+		str := fmt.Sprintf("`%v` (attempt %v) <t:%v:R>\n", d.Event, d.Attempt, d.Timestamp.Unix())
+		if d.Error == nil {
+			str += "✅ "
+		} else {
+			str += "❌ "
+		}
+		if d.Status != nil {
+			str += fmt.Sprintf("HTTP %v", *d.Status)
+		}
+		if d.Error != nil {
+			str += " " + bcr.AsCode(*d.Error)
+		}
+		str += fmt.Sprintf("\nDelivery `%v`", d.DeliveryID)
+
+		s = append(s, str+"\n\n")
+	}
+
+	_, _, err = ctx.ButtonPages(
+		bcr.StringPaginator(fmt.Sprintf("Deliveries for webhook %v", w.ID), db.EmbedColour, s, 10),
+		5*time.Minute,
+	)
+	return
+}
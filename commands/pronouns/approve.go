@@ -1,144 +1,353 @@
 package pronouns
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/termora/berry/commands/admin/auditlog"
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
 )
 
-func (bot *Bot) reactionAdd(m *gateway.MessageReactionAddEvent) {
-	// if this isn't the pronoun channel, return
-	if m.ChannelID != bot.Config.Bot.PronounChannel || !m.ChannelID.IsValid() {
-		return
+// prefix for the custom IDs of submission buttons and modals,
+// followed by the action and the submission ID, for example "pronoun-sub:approve:12"
+const submissionPrefix = "pronoun-sub:"
+
+func submissionButtons(id int) discord.ContainerComponents {
+	return discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Style:    discord.SuccessButtonStyle(),
+				CustomID: discord.ComponentID(fmt.Sprintf("%vapprove:%v", submissionPrefix, id)),
+				Label:    "Approve",
+			},
+			&discord.ButtonComponent{
+				Style:    discord.DangerButtonStyle(),
+				CustomID: discord.ComponentID(fmt.Sprintf("%vreject:%v", submissionPrefix, id)),
+				Label:    "Reject",
+			},
+			&discord.ButtonComponent{
+				Style:    discord.SecondaryButtonStyle(),
+				CustomID: discord.ComponentID(fmt.Sprintf("%vmerge:%v", submissionPrefix, id)),
+				Label:    "Merge into existing set",
+			},
+		},
+	}
+}
+
+// parseSubmissionID splits a submission custom ID into its action and submission ID
+func parseSubmissionID(customID discord.ComponentID) (action string, id int, ok bool) {
+	s := strings.TrimPrefix(string(customID), submissionPrefix)
+	if s == string(customID) {
+		return "", 0, false
 	}
 
-	// this probably shouldn't happen, but check anyway
-	if m.Member == nil {
-		return
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, false
 	}
 
-	// if the user is a bot return
-	if m.Member.User.Bot {
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[0], id, true
+}
+
+func (bot *Bot) interactionCreate(ic *gateway.InteractionCreateEvent) {
+	var err error
+	switch data := ic.Data.(type) {
+	case *discord.ButtonInteraction:
+		action, id, ok := parseSubmissionID(data.CustomID)
+		if !ok {
+			return
+		}
+		err = bot.submissionButton(ic, action, id)
+	case *discord.ModalInteraction:
+		if strings.HasPrefix(string(data.CustomID), "submit-pronouns-modal") {
+			err = bot.submitModal(ic, data)
+			break
+		}
+
+		action, id, ok := parseSubmissionID(data.CustomID)
+		if !ok {
+			return
+		}
+		err = bot.submissionModal(ic, data, action, id)
+	default:
 		return
 	}
+	if err != nil {
+		log.Errorf("handling pronoun submission interaction: %v", err)
+	}
+}
 
-	s, _ := bot.Router.StateFromGuildID(m.GuildID)
+// submitModal handles the modal opened by the /submit pronouns command
+func (bot *Bot) submitModal(ic *gateway.InteractionCreateEvent, data *discord.ModalInteraction) (err error) {
+	if bot.Config.Bot.PronounChannel == 0 {
+		return bot.respondEphemeral(ic, "We aren't accepting new pronoun submissions through the bot. You might be able to ask in the support server.")
+	}
 
-	var exists bool
+	// the modal's custom ID is "submit-pronouns-modal:<language>", the form inputs are the language's slot keys
+	lang := db.DefaultLanguage
+	if parts := strings.SplitN(string(data.CustomID), ":", 2); len(parts) == 2 {
+		lang = parts[1]
+	}
+	l, ok := db.PronounLanguageByCode(lang)
+	if !ok {
+		return bot.respondEphemeral(ic, "That isn't a language we have pronouns for!")
+	}
 
-	con, cancel := bot.DB.Context()
-	defer cancel()
+	vals := modalValues(data)
+	p := db.PronounSet{Language: l.Code}
+	for _, slot := range l.Slots {
+		p.Forms = append(p.Forms, vals[discord.ComponentID(slot.Key)])
+	}
 
-	err := bot.DB.QueryRow(con, "select exists (select * from pronoun_msgs where message_id = $1)", m.MessageID).Scan(&exists)
+	s, _ := bot.Router.StateFromGuildID(ic.GuildID)
+	resp, err := bot.submitSet(s, *ic.Sender(), p)
 	if err != nil {
-		log.Errorf("Error getting pronoun message: %v", err)
-		return
-	}
-	if !exists {
-		return
+		log.Errorf("submitting pronouns: %v", err)
+		return bot.respondEphemeral(ic, "There was an unknown error while submitting these pronouns. Try again?")
 	}
+	return bot.respondEphemeral(ic, "%v", resp)
+}
 
-	// if it's not the approve emoji, return
-	if m.Emoji.Name != "✅" {
-		return
+func (bot *Bot) submissionButton(ic *gateway.InteractionCreateEvent, action string, id int) (err error) {
+	if !bot.IsDirector(ic.Member) {
+		return bot.respondEphemeral(ic, "You're not allowed to review pronoun submissions.")
 	}
 
-	var isStaff bool
-	for _, u := range bot.Config.Bot.BotOwners {
-		if u == m.Member.User.ID {
-			isStaff = true
-			break
+	s, _ := bot.Router.StateFromGuildID(ic.GuildID)
+
+	switch action {
+	case "approve":
+		sub, set, err := bot.DB.ApprovePronounSubmission(id, ic.SenderID())
+		if err != nil {
+			return bot.resolveError(ic, id, err)
 		}
+
+		_, err = auditlog.New(bot.Bot).SendLog(set.ID, auditlog.PronounsEntry, auditlog.CreateAction, nil, set, ic.SenderID(), nil)
+		if err != nil {
+			log.Errorf("Error sending audit log for pronoun set %v: %v", set.ID, err)
+		}
+
+		return bot.resolved(ic, sub, fmt.Sprintf("Approved by %v as `%v`", ic.Sender().Mention(), set.ID))
+	case "reject":
+		return s.RespondInteraction(ic.ID, ic.Token, api.InteractionResponse{
+			Type: api.ModalResponse,
+			Data: &api.InteractionResponseData{
+				Title:    option.NewNullableString("Reject submission"),
+				CustomID: option.NewNullableString(fmt.Sprintf("%vreject:%v", submissionPrefix, id)),
+				Components: &discord.ContainerComponents{
+					&discord.ActionRowComponent{
+						&discord.TextInputComponent{
+							CustomID:    "reason",
+							Style:       discord.TextInputParagraphStyle,
+							Label:       "Reason (sent to the submitter)",
+							ValueLimits: [2]int{1, 1000},
+							Required:    true,
+						},
+					},
+				},
+			},
+		})
+	case "merge":
+		return s.RespondInteraction(ic.ID, ic.Token, api.InteractionResponse{
+			Type: api.ModalResponse,
+			Data: &api.InteractionResponseData{
+				Title:    option.NewNullableString("Merge submission"),
+				CustomID: option.NewNullableString(fmt.Sprintf("%vmerge:%v", submissionPrefix, id)),
+				Components: &discord.ContainerComponents{
+					&discord.ActionRowComponent{
+						&discord.TextInputComponent{
+							CustomID:    "set",
+							Style:       discord.TextInputShortStyle,
+							Label:       "Existing set (ID or forms)",
+							Placeholder: option.NewNullableString("Example: they/them/their/theirs/themselves"),
+							ValueLimits: [2]int{1, 200},
+							Required:    true,
+						},
+					},
+				},
+			},
+		})
 	}
+	return nil
+}
 
-	for _, r := range m.Member.RoleIDs {
-		for _, s := range bot.Config.Bot.Admins {
-			if r == s {
-				isStaff = true
-				break
-			}
+func (bot *Bot) submissionModal(ic *gateway.InteractionCreateEvent, data *discord.ModalInteraction, action string, id int) (err error) {
+	if !bot.IsDirector(ic.Member) {
+		return bot.respondEphemeral(ic, "You're not allowed to review pronoun submissions.")
+	}
+
+	vals := modalValues(data)
+
+	switch action {
+	case "reject":
+		sub, err := bot.DB.RejectPronounSubmission(id, ic.SenderID(), vals["reason"])
+		if err != nil {
+			return bot.resolveError(ic, id, err)
 		}
 
-		for _, s := range bot.Config.Bot.Directors {
-			if r == s {
-				isStaff = true
-				break
-			}
+		return bot.resolved(ic, sub, fmt.Sprintf("Rejected by %v:\n> %v", ic.Sender().Mention(), vals["reason"]))
+	case "merge":
+		set, err := bot.DB.FindPronounSet(vals["set"])
+		if err != nil {
+			return bot.respondEphemeral(ic, "Couldn't find that pronoun set. Give either its ID or enough forms to identify it.")
 		}
-	}
 
-	// if the member isn't staff, return
-	if !isStaff {
-		// also remove their reaction if possible
-		if p, _ := s.Permissions(m.ChannelID, bot.Router.Bot.ID); p.Has(discord.PermissionManageMessages) && m.Emoji.Name == "✅" {
-			s.DeleteUserReaction(m.ChannelID, m.MessageID, m.UserID, "✅")
+		sub, err := bot.DB.MergePronounSubmission(id, ic.SenderID(), set.ID)
+		if err != nil {
+			return bot.resolveError(ic, id, err)
 		}
 
-		return
+		return bot.resolved(ic, sub, fmt.Sprintf("Merged into **%v** (`%v`) by %v", set, set.ID, ic.Sender().Mention()))
 	}
+	return nil
+}
 
-	var p db.PronounSet
-
-	con, cancel = bot.DB.Context()
-	defer cancel()
+// addLegacyButtons adds the review buttons to submissions carried over from the old reaction-based system,
+// so they can be resolved the same way as new ones.
+func (bot *Bot) addLegacyButtons() {
+	ch := bot.Config.Bot.PronounChannel
+	if !ch.IsValid() {
+		return
+	}
 
-	err = bot.DB.QueryRow(con, "select subjective, objective, poss_det, poss_pro, reflexive from pronoun_msgs where message_id = $1", m.MessageID).Scan(&p.Subjective, &p.Objective, &p.PossDet, &p.PossPro, &p.Reflexive)
+	subs, err := bot.DB.LegacyPronounSubmissions()
 	if err != nil {
-		log.Errorf("Error getting pronoun set: %v", err)
+		log.Errorf("Error getting legacy pronoun submissions: %v", err)
 		return
 	}
 
-	// add the pronouns!
-	_, err = bot.DB.AddPronoun(p)
-	if err != nil {
-		log.Errorf("Error adding pronoun set: %v", err)
-		// this is the only one we DM the person who approved it for
-		ch, chErr := s.CreatePrivateChannel(m.Member.User.ID)
-		if chErr != nil {
-			return
+	s, _ := bot.Router.StateFromGuildID(0)
+	for _, sub := range subs {
+		msg, err := s.Message(ch, sub.MessageID)
+		if err != nil {
+			// the message might have been deleted, these can still be resolved on the dashboard
+			log.Errorf("Error getting message for legacy pronoun submission %v: %v", sub.ID, err)
+			continue
+		}
+		if len(msg.Components) > 0 {
+			continue
+		}
+
+		buttons := submissionButtons(sub.ID)
+		_, err = s.EditMessageComplex(ch, sub.MessageID, api.EditMessageData{
+			Components: &buttons,
+		})
+		if err != nil {
+			log.Errorf("Error adding buttons to legacy pronoun submission %v: %v", sub.ID, err)
 		}
-		s.SendMessage(ch.ID, fmt.Sprintf("There was an error adding the pronoun set:\n```%v```", err))
-		return
 	}
+}
 
-	// remove the message
-	con, cancel = bot.DB.Context()
-	defer cancel()
+func (bot *Bot) resolveError(ic *gateway.InteractionCreateEvent, id int, err error) error {
+	if errors.Is(err, db.ErrSubmissionResolved) {
+		return bot.respondEphemeral(ic, "That submission has already been resolved.")
+	}
+	var exists *db.ErrPronounExists
+	if errors.As(err, &exists) {
+		return bot.respondEphemeral(ic, fmt.Sprintf("That set is already in the database with ID `%v`. Use the merge button to merge the submission into it instead.", exists.ID))
+	}
 
-	_, err = bot.DB.Exec(con, "delete from pronoun_msgs where message_id = $1", m.MessageID)
-	if err != nil {
-		log.Errorf("Error deleting message from database: %v", err)
-		return
+	log.Errorf("Error resolving pronoun submission %v: %v", id, err)
+	return bot.respondEphemeral(ic, "There was an internal error resolving that submission.")
+}
+
+// resolved updates the submission message and DMs the submitter
+func (bot *Bot) resolved(ic *gateway.InteractionCreateEvent, sub db.PronounSubmission, status string) (err error) {
+	s, _ := bot.Router.StateFromGuildID(ic.GuildID)
+
+	var embeds []discord.Embed
+	if ic.Message != nil && len(ic.Message.Embeds) > 0 {
+		e := ic.Message.Embeds[0]
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Status",
+			Value: status,
+		})
+		e.Footer = &discord.EmbedFooter{
+			Icon: ic.Sender().AvatarURL(),
+			Text: fmt.Sprintf("Submission ID: %v\nResolved by %v (%v)", sub.ID, ic.Sender().Tag(), ic.SenderID()),
+		}
+		e.Timestamp = discord.NowTimestamp()
+		embeds = append(embeds, e)
 	}
 
-	// get the message
-	msg, err := s.Message(m.ChannelID, m.MessageID)
+	err = s.RespondInteraction(ic.ID, ic.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &embeds,
+			Components: &discord.ContainerComponents{},
+		},
+	})
 	if err != nil {
-		log.Errorf("Error getting message: %v", err)
-		return
+		log.Errorf("Error updating pronoun submission message: %v", err)
 	}
 
-	if len(msg.Embeds) < 1 {
-		return
+	// submissions carried over from the old system don't have a submitter
+	if !sub.UserID.IsValid() {
+		return nil
 	}
 
-	e := msg.Embeds[0]
-	e.Fields = append(e.Fields, discord.EmbedField{
-		Name:  "Submitted at",
-		Value: fmt.Sprintf("> %v", msg.Timestamp.Time().Format("Jan 02 2006, 15:04:05 UTC")),
-	})
-	e.Footer = &discord.EmbedFooter{
-		Icon: m.Member.User.AvatarURL(),
-		Text: fmt.Sprintf("Approved by %v#%v\n(%v)", m.Member.User.Username, m.Member.User.Discriminator, m.Member.User.ID),
+	var into *db.PronounSet
+	if sub.MergedInto != nil {
+		if set, err := bot.DB.PronounSetByID(*sub.MergedInto); err == nil {
+			into = set
+		}
 	}
-	e.Timestamp = discord.NowTimestamp()
+	msg := sub.ResolvedMessage(into)
 
-	_, err = s.EditEmbeds(msg.ChannelID, msg.ID, e)
+	ch, err := s.CreatePrivateChannel(sub.UserID)
 	if err != nil {
-		log.Errorf("Error editing message: %v", err)
-		return
+		log.Errorf("Error creating DM channel for %v: %v", sub.UserID, err)
+		return nil
+	}
+
+	_, err = s.SendMessageComplex(ch.ID, api.SendMessageData{
+		Content:         msg,
+		AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+	})
+	if err != nil {
+		// the submitter might have DMs closed
+		log.Debugf("Error sending submission DM to %v: %v", sub.UserID, err)
+	}
+	return nil
+}
+
+func (bot *Bot) respondEphemeral(ic *gateway.InteractionCreateEvent, tmpl string, v ...interface{}) error {
+	s, _ := bot.Router.StateFromGuildID(ic.GuildID)
+	return s.RespondInteraction(ic.ID, ic.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(fmt.Sprintf(tmpl, v...)),
+			Flags:   api.EphemeralResponse,
+		},
+	})
+}
+
+// modalValues returns the values of all text inputs in a modal, keyed by custom ID
+func modalValues(data *discord.ModalInteraction) map[discord.ComponentID]string {
+	vals := map[discord.ComponentID]string{}
+	for _, cc := range data.Components {
+		v, ok := cc.(*discord.ActionRowComponent)
+		if !ok {
+			continue
+		}
+
+		for _, c := range *v {
+			v, ok := c.(*discord.TextInputComponent)
+			if ok {
+				vals[v.CustomID] = strings.TrimSpace(v.Value.Val)
+			}
+		}
 	}
+	return vals
 }
@@ -0,0 +1,162 @@
+// Package examples renders pronoun sets in example sentences.
+// It's used by the bot's pronoun commands, the API, and the website.
+//
+// Every language in db.PronounLanguages has a directory of templates named after its code,
+// which get the set's forms keyed by the language's slot keys.
+package examples
+
+import (
+	"embed"
+	"errors"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/termora/berry/db"
+)
+
+//go:embed */[0-9]*
+var fs embed.FS
+
+// ErrMixedLanguages is returned if sets in different languages are rendered together
+var ErrMixedLanguages = errors.New("sets are in different languages")
+
+var (
+	templates = map[string]*template.Template{}
+	counts    = map[string]int{}
+)
+
+// initialise templates for every language
+func init() {
+	for _, l := range db.PronounLanguages {
+		files, err := fs.ReadDir(l.Code)
+		if err != nil {
+			continue
+		}
+
+		templates[l.Code] = template.Must(template.New("").Funcs(template.FuncMap{
+			"title": strings.Title,
+		}).ParseFS(fs, l.Code+"/[0-9]*"))
+		counts[l.Code] = len(files)
+	}
+}
+
+// Count returns the number of example templates for the given language.
+func Count(lang string) int {
+	if lang == "" {
+		lang = db.DefaultLanguage
+	}
+	return counts[lang]
+}
+
+// Render renders every example template with the given sets.
+// If more than one set is given, the sets alternate sentence by sentence.
+// If name isn't empty, it's used in place of the first form.
+func Render(sets []*db.PronounSet, name string) (pages []string, err error) {
+	if len(sets) == 0 {
+		return nil, db.ErrNoForms
+	}
+
+	lang := sets[0].Lang()
+	for _, set := range sets[1:] {
+		if set.Lang() != lang {
+			return nil, ErrMixedLanguages
+		}
+	}
+
+	for i := 0; i < counts[lang]; i++ {
+		page, err := render(templates[lang], strconv.Itoa(i), sets, name)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Title returns a short title for the given sets, like "they/them pronouns",
+// or "she/they pronouns" for more than one set.
+func Title(sets []*db.PronounSet) (title string) {
+	if len(sets) == 1 {
+		forms := sets[0].FormList()
+		if len(forms) > 2 {
+			forms = forms[:2]
+		}
+		title = strings.Join(forms, "/") + " pronouns"
+	} else {
+		var subj []string
+		for _, set := range sets {
+			subj = append(subj, set.FormList()[0])
+		}
+		title = strings.Join(subj, "/") + " pronouns"
+	}
+
+	if len(sets) > 0 {
+		if l, ok := db.PronounLanguageByCode(sets[0].Lang()); ok && l.Code != db.DefaultLanguage {
+			title += " (" + l.Name + ")"
+		}
+	}
+	return title
+}
+
+func render(t *template.Template, tmpl string, sets []*db.PronounSet, name string) (string, error) {
+	var b strings.Builder
+
+	rendered := make([][]string, 0, len(sets))
+	for _, set := range sets {
+		data := set.FormMap()
+		if l, ok := db.PronounLanguageByCode(set.Lang()); ok && name != "" {
+			data[l.Slots[0].Key] = name
+		}
+
+		err := t.ExecuteTemplate(&b, tmpl, data)
+		if err != nil {
+			return "", err
+		}
+		rendered = append(rendered, splitSentences(b.String()))
+		b.Reset()
+	}
+
+	// the templates are the same for every set, so this should only happen
+	// if one of the forms itself looks like the end of a sentence
+	for _, r := range rendered[1:] {
+		if len(r) != len(rendered[0]) {
+			return strings.Join(rendered[0], ""), nil
+		}
+	}
+
+	for i := range rendered[0] {
+		b.WriteString(rendered[i%len(rendered)][i])
+	}
+	return b.String(), nil
+}
+
+// splitSentences splits s after sentence-ending punctuation and paragraph breaks.
+// Whitespace is kept with the preceding sentence, so joining the output returns s.
+func splitSentences(s string) (out []string) {
+	start := 0
+	for i := 0; i < len(s)-1; i++ {
+		switch {
+		case strings.IndexByte(".!?", s[i]) != -1 && isSpace(s[i+1]):
+		case s[i] == '\n' && s[i+1] == '\n':
+		default:
+			continue
+		}
+
+		end := i + 1
+		for end < len(s) && isSpace(s[end]) {
+			end++
+		}
+		out = append(out, s[start:end])
+		start, i = end, end-1
+	}
+
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t'
+}
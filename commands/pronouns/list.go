@@ -25,11 +25,30 @@ func (bot *Bot) list(ctx *bcr.Context) (err error) {
 		footerTmpl = "Sorting by # of uses | Page %v/%v"
 	}
 
-	p, err := bot.DB.Pronouns(order)
+	lang := ctx.GetStringFlag("language")
+	title := "List of pronouns (%v)"
+	if lang != "" {
+		l, ok := db.PronounLanguageByCode(lang)
+		if !ok {
+			return unknownLanguage(ctx)
+		}
+		lang = l.Code
+		title = "List of " + l.Name + " pronouns (%v)"
+	}
+
+	p, err := bot.DB.LanguagePronouns(lang, order)
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
 
+	var userCounts map[int]int64
+	if order == db.UsesPronounOrder {
+		userCounts, err = bot.DB.PronounUserCounts()
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+	}
+
 	if order == db.RandomPronounOrder {
 		rand.Shuffle(len(p), func(i, j int) {
 			p[i], p[j] = p[j], p[i]
@@ -50,7 +69,11 @@ func (bot *Bot) list(ctx *bcr.Context) (err error) {
 		}
 		b.WriteString(p.String())
 		if order == db.UsesPronounOrder {
-			b.WriteString(" (" + english.Plural(int(p.Uses), "use", "uses") + ")")
+			b.WriteString(" (" + english.Plural(int(p.Uses), "use", "uses"))
+			if n := userCounts[p.ID]; n > 0 {
+				b.WriteString(", " + english.Plural(int(n), "user", "users"))
+			}
+			b.WriteString(")")
 		}
 		b.WriteRune('\n')
 		count++
@@ -60,7 +83,7 @@ func (bot *Bot) list(ctx *bcr.Context) (err error) {
 	e := make([]discord.Embed, 0)
 	for i, page := range s {
 		e = append(e, discord.Embed{
-			Title:       fmt.Sprintf("List of pronouns (%v)", len(p)),
+			Title:       fmt.Sprintf(title, len(p)),
 			Description: page,
 			Color:       db.EmbedColour,
 			Footer: &discord.EmbedFooter{
@@ -1,37 +1,25 @@
 package pronouns
 
 import (
-	"embed"
+	"fmt"
 	"strings"
-	"text/template"
+	"sync"
 	"time"
 
 	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/spf13/pflag"
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/bot"
+	"github.com/termora/berry/db"
 )
 
-//go:embed examples/*
-var fs embed.FS
-
-var templates = template.Must(template.New("").Funcs(funcs()).ParseFS(fs, "examples/*"))
-var tmplCount int
-
-// initialise number of templates
-func init() {
-	files, err := fs.ReadDir("examples")
-	if err != nil {
-		panic(err)
-	}
-	tmplCount = len(files)
-}
-
 type Bot struct {
 	*bot.Bot
 
 	submitCooldown *ttlcache.Cache
+	pk             PluralKit
 }
 
 // Init ...
@@ -39,6 +27,7 @@ func Init(b *bot.Bot) (m string, list []*bcr.Command) {
 	bot := &Bot{
 		Bot:            b,
 		submitCooldown: ttlcache.NewCache(),
+		pk:             NewPluralKit(b.Config.Bot.PluralKitURL),
 	}
 	bot.submitCooldown.SkipTTLExtensionOnHit(true)
 
@@ -52,6 +41,7 @@ func Init(b *bot.Bot) (m string, list []*bcr.Command) {
 			fs.BoolP("random", "r", false, "Sort pronouns randomly")
 			fs.BoolP("alphabetical", "a", false, "Sort pronouns alphabetically")
 			fs.BoolP("by-uses", "u", false, "Sort pronouns by number of uses")
+			fs.StringP("language", "l", "", "Only show pronouns in this language")
 			return fs
 		},
 
@@ -66,6 +56,8 @@ func Init(b *bot.Bot) (m string, list []*bcr.Command) {
 		Summary: "Submit a pronoun set",
 		Usage:   "<pronouns, forms separated with />",
 
+		Flags: languageFlag,
+
 		Blacklistable: true,
 		Command:       bot.submit,
 	}))
@@ -84,46 +76,176 @@ func Init(b *bot.Bot) (m string, list []*bcr.Command) {
 		Name:    "pronouns",
 		Aliases: []string{"pronoun", "neopronoun", "neopronouns"},
 
-		Summary: "Show pronouns (with optional name) used in a sentence",
-		Usage:   "<pronouns> [name]",
+		Summary:     "Show pronouns (with optional name) used in a sentence",
+		Description: "Multiple sets can be shown together, either separated with a plus (`he/him+xe/xem`) or as a list of subjective forms (`she/they`).",
+		Usage:       "<pronouns> [name]",
+
+		Flags: languageFlag,
 
 		Blacklistable: true,
 		Cooldown:      time.Second,
 		SlashCommand:  bot.use,
-		Options: &[]discord.CommandOption{
-			&discord.StringOption{
-				OptionName:  "pronouns",
-				Description: "The pronouns to show",
-				Required:    true,
-			},
-			&discord.StringOption{
-				OptionName:  "name",
-				Description: "The name to use",
-				Required:    false,
-			},
-		},
 	})
 
 	pronouns.AddSubcommand(&bcr.Command{
 		Name:          "custom",
 		Summary:       "Show custom pronouns that aren't in the bot",
+		Description:   "Multiple sets can be shown together by separating them with a plus (`+`).",
 		Usage:         "<pronoun set, space or slash separated>",
+		Flags:         languageFlag,
 		Blacklistable: true,
 		Cooldown:      time.Second,
 		Command:       bot.custom,
 	})
 
+	pronouns.AddSubcommand(&bcr.Command{
+		Name:          "user",
+		Aliases:       []string{"profile"},
+		Summary:       "Show a user's saved pronouns",
+		Usage:         "[user]",
+		Blacklistable: true,
+		Cooldown:      time.Second,
+		SlashCommand:  bot.userPronouns,
+	})
+
+	pronouns.AddSubcommand(&bcr.Command{
+		Name:          "save",
+		Summary:       "Save a pronoun set to your profile",
+		Description:   "Sets in the bot can be given with as many forms as needed to find them, custom sets need all forms.",
+		Usage:         "<pronouns> [name]",
+		Flags:         languageFlag,
+		Blacklistable: true,
+		Cooldown:      time.Second,
+		SlashCommand:  bot.savePronouns,
+	})
+
+	pronouns.AddSubcommand(&bcr.Command{
+		Name:          "remove",
+		Aliases:       []string{"unsave"},
+		Summary:       "Remove a pronoun set from your profile",
+		Usage:         "<pronouns|all>",
+		Blacklistable: true,
+		Cooldown:      time.Second,
+		SlashCommand:  bot.removePronouns,
+	})
+
+	pronouns.AddSubcommand(&bcr.Command{
+		Name:          "pluralkit",
+		Aliases:       []string{"pk"},
+		Summary:       "Show a PluralKit member's pronouns",
+		Description:   "Takes a link to (or ID of) a proxied message, or a PluralKit member ID. You can also reply to a proxied message.",
+		Usage:         "<message link|member ID>",
+		Flags:         languageFlag,
+		Blacklistable: true,
+		Cooldown:      5 * time.Second,
+		SlashCommand:  bot.pluralkit,
+	})
+
 	pronouns.AddSubcommand(bot.Router.AliasMust("list", []string{"l"}, []string{"list-pronouns"}, nil))
 	pronouns.AddSubcommand(bot.Router.AliasMust("submit", nil, []string{"submit-pronouns"}, nil))
 	pronouns.AddSubcommand(bot.Router.AliasMust("random", []string{"r"}, []string{"random-pronouns"}, nil))
 
-	bot.Router.AddHandler(bot.reactionAdd)
+	// slash commands can't have options *and* subcommands, so /pronouns is a group instead
+	languageOption := &discord.StringOption{
+		OptionName:  "language",
+		Description: "The language of the pronouns (defaults to English)",
+		Required:    false,
+		Choices:     db.PronounLanguageChoices(),
+	}
+
+	bot.Router.AddGroup(&bcr.Group{
+		Name:        "pronouns",
+		Description: "Show pronouns and users' saved pronouns",
+		Subcommands: []*bcr.Command{
+			{
+				Name:          "show",
+				Summary:       "Show pronouns (with optional name) used in a sentence",
+				Blacklistable: true,
+				SlashCommand:  bot.use,
+				Options: &[]discord.CommandOption{
+					discord.NewStringOption("pronouns", "The pronouns to show (separate multiple sets with +)", true),
+					discord.NewStringOption("name", "The name to use", false),
+					languageOption,
+				},
+			},
+			{
+				Name:          "user",
+				Summary:       "Show a user's saved pronouns",
+				Blacklistable: true,
+				SlashCommand:  bot.userPronouns,
+				Options: &[]discord.CommandOption{
+					discord.NewUserOption("member", "The user to show pronouns for (defaults to you)", false),
+				},
+			},
+			{
+				Name:          "save",
+				Summary:       "Save a pronoun set to your profile",
+				Blacklistable: true,
+				SlashCommand:  bot.savePronouns,
+				Options: &[]discord.CommandOption{
+					discord.NewStringOption("pronouns", "The pronouns to save (all forms, separated with /, for custom sets)", true),
+					discord.NewStringOption("name", "The name to use with these pronouns", false),
+					languageOption,
+				},
+			},
+			{
+				Name:          "remove",
+				Summary:       "Remove a pronoun set from your profile",
+				Blacklistable: true,
+				SlashCommand:  bot.removePronouns,
+				Options: &[]discord.CommandOption{
+					discord.NewStringOption("pronouns", `The pronouns to remove ("all" to clear your profile)`, true),
+				},
+			},
+			{
+				Name:          "pluralkit",
+				Summary:       "Show a PluralKit member's pronouns",
+				Blacklistable: true,
+				Cooldown:      5 * time.Second,
+				SlashCommand:  bot.pluralkit,
+				Options: &[]discord.CommandOption{
+					discord.NewStringOption("target", "A link to a proxied message, or a PluralKit member ID", true),
+					languageOption,
+				},
+			},
+		},
+	})
+
+	b.AddUserCommand("Show pronouns", func(ctx *bcr.SlashContext, u discord.User) error {
+		return bot.showProfile(ctx, u)
+	})
+
+	bot.Router.AddHandler(bot.interactionCreate)
+
+	state, _ := bot.Router.StateFromGuildID(0)
+
+	var o sync.Once
+	state.AddHandler(func(_ *gateway.ReadyEvent) {
+		o.Do(func() {
+			go bot.addLegacyButtons()
+		})
+	})
 
 	return "Pronoun commands", append(list, pronouns)
 }
 
-func funcs() map[string]interface{} {
-	return map[string]interface{}{
-		"title": strings.Title,
+func languageFlag(fs *pflag.FlagSet) *pflag.FlagSet {
+	fs.StringP("language", "l", db.DefaultLanguage, "The language of the pronouns")
+	return fs
+}
+
+func unknownLanguage(ctx bcr.Contexter) error {
+	var codes []string
+	for _, l := range db.PronounLanguages {
+		codes = append(codes, fmt.Sprintf("`%v` (%v)", l.Code, l.Name))
+	}
+
+	return ctx.SendEphemeral("That isn't a language we have pronouns for! Available languages are: " + strings.Join(codes, ", "))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
 	}
+	return b
 }
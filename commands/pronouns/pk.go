@@ -0,0 +1,191 @@
+package pronouns
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/starshine-sys/bcr"
+	"github.com/starshine-sys/pkgo"
+	"github.com/termora/berry/commands/pronouns/examples"
+	"github.com/termora/berry/db"
+)
+
+var (
+	messageLinkRegex = regexp.MustCompile(`channels/(?:\d+|@me)/\d+/(\d+)`)
+	pkMemberIDRegex  = regexp.MustCompile(`^[a-zA-Z]{5,6}$`)
+
+	// PluralKit's pronoun field is free text, so split it on anything that usually separates sets
+	pkSeparatorRegex = regexp.MustCompile(`[,;|&\n]+|\s+(?:or|and)\s+`)
+	pkParensRegex    = regexp.MustCompile(`\(.*?\)|\[.*?\]`)
+	pkSlashRegex     = regexp.MustCompile(`\s*/\s*`)
+)
+
+// maxPKSets is the maximum number of sets read from a member's pronouns
+const maxPKSets = 5
+
+func (bot *Bot) pluralkit(ctx bcr.Contexter) (err error) {
+	input := ctx.GetStringFlag("target")
+	if v, ok := ctx.(*bcr.Context); ok {
+		input = strings.TrimSpace(strings.Join(v.Args, " "))
+		// replying to a proxied message works too
+		if input == "" && v.Message.Reference != nil && v.Message.Reference.MessageID.IsValid() {
+			input = v.Message.Reference.MessageID.String()
+		}
+	}
+
+	if input == "" {
+		return ctx.SendEphemeral("You didn't give a message link, message ID, or PluralKit member ID!")
+	}
+
+	m, err := bot.pkMember(input)
+	if err != nil {
+		switch err {
+		case ErrPKNotFound:
+			return ctx.SendEphemeral("PluralKit couldn't find that message or member.")
+		case pkgo.ErrInvalidID:
+			return ctx.SendEphemeral("That isn't a valid message link, message ID, or PluralKit member ID.")
+		case pkgo.ErrRateLimit:
+			return ctx.SendEphemeral("We're being rate limited by PluralKit, please try again in a few seconds.")
+		}
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if strings.TrimSpace(m.Pronouns) == "" {
+		return ctx.SendEphemeral(fmt.Sprintf("%v doesn't have any (public) pronouns set.", m.DisplayedName()))
+	}
+
+	lang, ok := db.PronounLanguageByCode(ctx.GetStringFlag("language"))
+	if !ok {
+		return unknownLanguage(ctx)
+	}
+
+	sets, unmatched, err := bot.matchPKPronouns(lang, m.Pronouns)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(sets) == 0 {
+		return ctx.SendEphemeral(fmt.Sprintf(
+			"Couldn't find any pronoun sets matching %v's pronouns (`%v`). If they're custom pronouns, try `%vpronouns custom` with all %v forms!",
+			m.DisplayedName(), bcr.EscapeBackticks(m.Pronouns), bot.Config.Bot.Prefixes[0], len(lang.Slots),
+		))
+	}
+
+	for _, set := range sets {
+		if set.ID != 0 {
+			go bot.DB.IncrementPronounUse(set)
+		}
+	}
+
+	if examples.Count(lang.Code) == 0 {
+		return ctx.SendEphemeral("There are no examples available for pronouns! If you think this is in error, please join the bot support server and ask there.")
+	}
+
+	e, err := bot.pronounEmbeds(sets, m.DisplayedName())
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	desc := fmt.Sprintf("PluralKit pronouns: `%v`\n\n", bcr.EscapeBackticks(m.Pronouns))
+	if len(unmatched) > 0 {
+		desc += fmt.Sprintf("Couldn't find %v, try `%vpronouns custom` for those.\n\n", strings.Join(unmatched, ", "), bot.Config.Bot.Prefixes[0])
+	}
+
+	for i := range e {
+		e[i].Title = m.DisplayedName() + "'s pronouns"
+		if m.AvatarURL != "" {
+			e[i].Thumbnail = &discord.EmbedThumbnail{URL: m.AvatarURL}
+		}
+		if m.Color.IsValid() {
+			e[i].Color = discord.Color(m.Color.ToInt())
+		}
+	}
+	e[0].Description = desc + e[0].Description
+
+	if v, ok := ctx.(*bcr.Context); ok {
+		_, err = v.PagedEmbed(e, false)
+	} else {
+		_, _, err = ctx.ButtonPages(e, 15*time.Minute)
+	}
+	return
+}
+
+// pkMember resolves a message link, message ID, or member ID to a PluralKit member
+func (bot *Bot) pkMember(input string) (*pkgo.Member, error) {
+	if match := messageLinkRegex.FindStringSubmatch(input); match != nil {
+		input = match[1]
+	}
+
+	if pkMemberIDRegex.MatchString(input) {
+		return bot.pk.Member(input)
+	}
+
+	id, err := discord.ParseSnowflake(input)
+	if err != nil {
+		return nil, pkgo.ErrInvalidID
+	}
+
+	msg, err := bot.pk.Message(discord.MessageID(id))
+	if err != nil {
+		return nil, err
+	}
+	// messages sent without a member (or with a private member) don't have one
+	if msg.Member.ID == "" {
+		return nil, ErrPKNotFound
+	}
+	return &msg.Member, nil
+}
+
+// matchPKPronouns matches a PluralKit pronoun field against the database.
+// Sets that aren't found, but have all forms, are shown as custom sets.
+func (bot *Bot) matchPKPronouns(lang db.PronounLanguage, field string) (sets []*db.PronounSet, unmatched []string, err error) {
+	field = pkParensRegex.ReplaceAllString(field, "")
+
+	for _, s := range pkSeparatorRegex.Split(field, -1) {
+		s = strings.TrimSpace(pkSlashRegex.ReplaceAllString(s, "/"))
+		if s == "" || len(sets) >= maxPKSets {
+			continue
+		}
+
+		matches, mixed, err := bot.DB.ParsePronouns(lang.Code, s)
+		if err != nil {
+			if errors.Cause(err) != pgx.ErrNoRows && err != db.ErrTooManyForms {
+				return nil, nil, err
+			}
+
+			set, err := db.NewPronounSet(lang.Code, strings.Split(s, "/"))
+			if err != nil {
+				unmatched = append(unmatched, "`"+bcr.EscapeBackticks(s)+"`")
+				continue
+			}
+			matches = []*db.PronounSet{&set}
+		} else if !mixed {
+			matches = matches[:1]
+		}
+
+		for _, set := range matches {
+			if !containsSet(sets, set) {
+				sets = append(sets, set)
+			}
+		}
+	}
+
+	if len(sets) > maxPKSets {
+		sets = sets[:maxPKSets]
+	}
+	return sets, unmatched, nil
+}
+
+func containsSet(sets []*db.PronounSet, set *db.PronounSet) bool {
+	for _, s := range sets {
+		if (set.ID != 0 && s.ID == set.ID) || s.String() == set.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package pronouns
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/pkgo"
+)
+
+// DefaultPluralKitURL is the base URL of the public PluralKit API
+const DefaultPluralKitURL = "https://api.pluralkit.me/v2"
+
+// ErrPKNotFound is returned when a message or member isn't found by PluralKit
+var ErrPKNotFound = errors.New("pluralkit: not found")
+
+// PluralKit is the part of the PluralKit API used by the bot.
+type PluralKit interface {
+	// Message returns the PluralKit message for the given ID, which can be either the original or the proxied message
+	Message(id discord.MessageID) (*pkgo.Message, error)
+	// Member returns the member with the given 5-character ID
+	Member(id string) (*pkgo.Member, error)
+}
+
+// pkgo's Session only talks to the (deprecated) v1 API and can't be pointed anywhere else,
+// so this only uses its types.
+type pkClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+var _ PluralKit = (*pkClient)(nil)
+
+// NewPluralKit returns a PluralKit client using the API at baseURL, or the public API if it's empty
+func NewPluralKit(baseURL string) PluralKit {
+	if baseURL == "" {
+		baseURL = DefaultPluralKitURL
+	}
+
+	return &pkClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *pkClient) Message(id discord.MessageID) (m *pkgo.Message, err error) {
+	err = c.get("/messages/"+id.String(), &m)
+	return
+}
+
+func (c *pkClient) Member(id string) (m *pkgo.Member, err error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if len(id) < 5 || len(id) > 6 {
+		return nil, pkgo.ErrInvalidID
+	}
+
+	err = c.get("/members/"+id, &m)
+	return
+}
+
+func (c *pkClient) get(path string, v interface{}) error {
+	resp, err := c.client.Get(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(v)
+	case http.StatusNotFound:
+		return ErrPKNotFound
+	case http.StatusTooManyRequests:
+		return pkgo.ErrRateLimit
+	default:
+		return &pkgo.ErrStatusNot200{Code: resp.StatusCode, Status: resp.Status}
+	}
+}
@@ -0,0 +1,102 @@
+package pronouns
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/pkgo"
+)
+
+// fakePluralKit serves canned responses in the same shape as the PluralKit v2 API
+func fakePluralKit(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/members/abcde", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "abcde", "name": "Alex", "display_name": "Alex 🌸", "pronouns": "they/them, xe/xem"}`))
+	})
+	mux.HandleFunc("/messages/100", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "100", "original": "99", "system": {"id": "zyxwv"}, "member": {"id": "abcde", "name": "Alex", "pronouns": "they/them"}}`))
+	})
+	// the message exists, but its system was deleted
+	mux.HandleFunc("/messages/200", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 20001, "message": "System not found."}`))
+	})
+	mux.HandleFunc("/members/ratel", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"code": 0, "message": "429: too many requests", "retry_after": 1000}`))
+	})
+	mux.HandleFunc("/members/broke", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPluralKitMember(t *testing.T) {
+	pk := NewPluralKit(fakePluralKit(t).URL + "/")
+
+	m, err := pk.Member(" ABCDE ")
+	if err != nil {
+		t.Fatalf("Member: unexpected error: %v", err)
+	}
+	if m.ID != "abcde" || m.Pronouns != "they/them, xe/xem" || m.DisplayedName() != "Alex 🌸" {
+		t.Errorf("Member: got %+v", m)
+	}
+
+	msg, err := pk.Message(discord.MessageID(100))
+	if err != nil {
+		t.Fatalf("Message: unexpected error: %v", err)
+	}
+	if msg.Member.ID != "abcde" || msg.Member.Pronouns != "they/them" {
+		t.Errorf("Message: got member %+v", msg.Member)
+	}
+}
+
+func TestPluralKitErrors(t *testing.T) {
+	pk := NewPluralKit(fakePluralKit(t).URL)
+
+	tests := []struct {
+		name string
+		get  func() error
+		want error
+	}{
+		{"system not found", func() error {
+			_, err := pk.Message(discord.MessageID(200))
+			return err
+		}, ErrPKNotFound},
+		{"unknown member", func() error {
+			_, err := pk.Member("qwert")
+			return err
+		}, ErrPKNotFound},
+		{"rate limited", func() error {
+			_, err := pk.Member("ratel")
+			return err
+		}, pkgo.ErrRateLimit},
+		{"invalid ID", func() error {
+			_, err := pk.Member("abc")
+			return err
+		}, pkgo.ErrInvalidID},
+	}
+
+	for _, test := range tests {
+		if err := test.get(); !errors.Is(err, test.want) {
+			t.Errorf("%v: got error %v, want %v", test.name, err, test.want)
+		}
+	}
+
+	_, err := pk.Member("broke")
+	var statusErr *pkgo.ErrStatusNot200
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusInternalServerError {
+		t.Errorf("server error: got error %v, want status 500", err)
+	}
+}
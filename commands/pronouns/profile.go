@@ -0,0 +1,213 @@
+package pronouns
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
+)
+
+func (bot *Bot) userPronouns(ctx bcr.Contexter) (err error) {
+	u := ctx.User()
+
+	if v, ok := ctx.(*bcr.Context); ok {
+		if len(v.Args) > 0 {
+			m, err := v.ParseMember(v.RawArgs)
+			if err == nil {
+				u = m.User
+			} else {
+				target, err := v.ParseUser(v.RawArgs)
+				if err != nil {
+					_, err = v.Send("User not found.")
+					return err
+				}
+				u = *target
+			}
+		}
+	} else {
+		target, err := ctx.GetUserFlag("member")
+		if err == nil && target != nil {
+			u = *target
+		}
+	}
+
+	return bot.showProfile(ctx, u)
+}
+
+// showProfile shows the given user's saved pronouns
+func (bot *Bot) showProfile(ctx bcr.Contexter, u discord.User) (err error) {
+	saved, err := bot.DB.UserPronouns(u.ID)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if len(saved) == 0 {
+		if u.ID == ctx.User().ID {
+			return ctx.SendEphemeral(fmt.Sprintf("You haven't saved any pronouns yet! Save a set to your profile with `%vpronouns save`.", bot.Config.Bot.Prefixes[0]))
+		}
+		return ctx.SendEphemeral(fmt.Sprintf("%v hasn't saved any pronouns yet.", u.Username))
+	}
+
+	e, err := bot.profileEmbeds(u, saved)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	if v, ok := ctx.(*bcr.Context); ok {
+		_, err = v.PagedEmbed(e, false)
+	} else {
+		_, _, err = ctx.ButtonPages(e, 15*time.Minute)
+	}
+	return
+}
+
+// profileEmbeds renders a user's saved sets.
+// Examples can't mix languages, so only sets in the same language as the first one are used in them.
+func (bot *Bot) profileEmbeds(u discord.User, saved []db.UserPronouns) (e []discord.Embed, err error) {
+	var (
+		sets []*db.PronounSet
+		name string
+		desc string
+	)
+
+	for i := range saved {
+		s := &saved[i]
+
+		desc += fmt.Sprintf("**%s**", &s.Set)
+		if s.Name != nil {
+			desc += fmt.Sprintf(" (%v)", *s.Name)
+			if name == "" {
+				name = *s.Name
+			}
+		}
+		desc += "\n"
+
+		if s.Set.Lang() == saved[0].Set.Lang() {
+			sets = append(sets, &s.Set)
+		}
+	}
+
+	e, err = bot.pronounEmbeds(sets, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range e {
+		e[i].Title = u.Username + "'s pronouns"
+		e[i].Thumbnail = &discord.EmbedThumbnail{URL: u.AvatarURL()}
+	}
+	e[0].Description = desc + "\nTo see these pronouns in action, use the arrow reactions on this message!"
+
+	return e, nil
+}
+
+func (bot *Bot) savePronouns(ctx bcr.Contexter) (err error) {
+	input := ctx.GetStringFlag("pronouns")
+	name := ctx.GetStringFlag("name")
+	if v, ok := ctx.(*bcr.Context); ok {
+		if len(v.Args) > 0 {
+			input = v.Args[0]
+		}
+		if len(v.Args) > 1 {
+			name = v.Args[1]
+		}
+	}
+
+	if input == "" {
+		return ctx.SendEphemeral("You didn't give any pronouns to save!")
+	}
+	if len(name) > 100 {
+		return ctx.SendEphemeral("That name is too long, the maximum is 100 characters.")
+	}
+
+	lang, ok := db.PronounLanguageByCode(ctx.GetStringFlag("language"))
+	if !ok {
+		return unknownLanguage(ctx)
+	}
+
+	sets, mixed, err := bot.DB.ParsePronouns(lang.Code, input)
+	if err != nil {
+		if errors.Cause(err) != pgx.ErrNoRows && err != db.ErrTooManyForms {
+			return bot.DB.InternalError(ctx, err)
+		}
+
+		// if the input isn't in the database, try saving it as a custom set
+		set, err := db.NewPronounSet(lang.Code, strings.Split(input, "/"))
+		if err != nil {
+			return ctx.SendEphemeral(fmt.Sprintf("Couldn't find any pronoun sets from your input. To save a custom set, give exactly %v forms, separated with a slash (`/`).", len(lang.Slots)))
+		}
+		sets = []*db.PronounSet{&set}
+	} else if len(sets) > 1 && !mixed {
+		var s []string
+		for _, set := range sets[:minInt(5, len(sets))] {
+			s = append(s, fmt.Sprintf("`%s`", set))
+		}
+		return ctx.SendEphemeral("Found more than one set matching your input! Please give more forms, for example: " + strings.Join(s, ", "))
+	}
+
+	var saved []string
+	for _, set := range sets {
+		_, err = bot.DB.AddUserPronouns(ctx.User().ID, *set, name)
+		if err != nil {
+			if err == db.ErrUserPronounsSaved {
+				continue
+			}
+			if err == db.ErrTooManyUserPronouns {
+				return ctx.SendEphemeral(fmt.Sprintf("You can't save more than %v pronoun sets to your profile.", db.MaxUserPronouns))
+			}
+			return bot.DB.InternalError(ctx, err)
+		}
+		saved = append(saved, fmt.Sprintf("**%s**", set))
+	}
+
+	if len(saved) == 0 {
+		return ctx.SendEphemeral("You've already saved those pronouns to your profile!")
+	}
+	return ctx.SendEphemeral(fmt.Sprintf("Saved %v to your profile!", strings.Join(saved, ", ")))
+}
+
+func (bot *Bot) removePronouns(ctx bcr.Contexter) (err error) {
+	input := ctx.GetStringFlag("pronouns")
+	if v, ok := ctx.(*bcr.Context); ok {
+		input = v.RawArgs
+	}
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	if input == "" {
+		return ctx.SendEphemeral("You didn't give any pronouns to remove! Use `all` to clear your profile.")
+	}
+
+	if input == "all" {
+		err = bot.DB.ClearUserPronouns(ctx.User().ID)
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+		return ctx.SendEphemeral("Removed all pronouns from your profile.")
+	}
+
+	saved, err := bot.DB.UserPronouns(ctx.User().ID)
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	for _, s := range saved {
+		if !strings.HasPrefix(strings.ToLower(s.Set.String()), input) {
+			continue
+		}
+
+		ok, err := bot.DB.RemoveUserPronouns(ctx.User().ID, s.ID)
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+		if ok {
+			return ctx.SendEphemeral(fmt.Sprintf("Removed **%s** from your profile.", &s.Set))
+		}
+	}
+
+	return ctx.SendEphemeral("You don't have that set saved to your profile.")
+}
@@ -1,17 +1,24 @@
 package pronouns
 
-import "github.com/starshine-sys/bcr"
+import (
+	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/db"
+)
 
 func (bot *Bot) random(ctx *bcr.Context) (err error) {
-	// we don't wanna repeat code so just call c.use with a random set
 	// get a random pronoun set
 	set, err := bot.DB.RandomPronouns()
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
-	// set the arguments to that set
-	ctx.RawArgs = set.String()
-	ctx.Args = []string{set.String()}
-	// return c.use
-	return bot.use(ctx)
+
+	go bot.DB.IncrementPronounUse(set)
+
+	e, err := bot.pronounEmbeds([]*db.PronounSet{set}, "")
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
+	}
+
+	_, err = ctx.PagedEmbed(e, false)
+	return
 }
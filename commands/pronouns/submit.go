@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
@@ -16,83 +18,121 @@ func (bot *Bot) submit(ctx *bcr.Context) (err error) {
 		return err
 	}
 
-	if ctx.RawArgs == "" {
+	lang, ok := db.PronounLanguageByCode(ctx.GetStringFlag("language"))
+	if !ok {
+		return unknownLanguage(ctx)
+	}
+
+	if len(ctx.Args) == 0 {
 		_, err = ctx.Send("You didn't give a pronoun set.")
 		return err
 	}
-	p := strings.Split(ctx.RawArgs, "/")
-	if len(p) < 5 {
+	p := strings.Split(strings.Join(ctx.Args, " "), "/")
+	if len(p) < len(lang.Slots) {
 		_, err = ctx.Send("You didn't give enough forms. Make sure you separate the forms with forward slashes (/).")
 		return
 	}
-	if len(p) > 5 {
-		_, err = ctx.Send("You gave too many forms. Make sure you have five forms, separated with forward slashes.")
+	if len(p) > len(lang.Slots) {
+		_, err = ctx.Sendf("You gave too many forms. Make sure you have %v forms, separated with forward slashes.", len(lang.Slots))
 		return
 	}
 
-	// normalize pronouns
-	for i := range p {
-		p[i] = strings.ToLower(strings.TrimSpace(p[i]))
+	resp, err := bot.submitSet(ctx.State, ctx.Author, db.PronounSet{
+		Language: lang.Code,
+		Forms:    p,
+	})
+	if err != nil {
+		return bot.DB.InternalError(ctx, err)
 	}
 
-	_, err = bot.DB.GetPronoun(p...)
-	if err == nil {
-		_, err = ctx.Send("That pronoun set already exists!")
-		return
+	_, err = ctx.NewMessage().Content(resp).BlockMentions().Send()
+	return
+}
+
+// submitSet adds a pronoun submission and sends it to the pronoun channel.
+// The returned string is the response to show the submitter.
+func (bot *Bot) submitSet(s *state.State, u discord.User, p db.PronounSet) (resp string, err error) {
+	// normalize pronouns
+	forms := p.FormList()
+	for i := range forms {
+		forms[i] = strings.ToLower(forms[i])
 	}
 
-	con, cancel := bot.DB.Context()
-	defer cancel()
+	p, err = db.NewPronounSet(p.Lang(), forms)
+	if err != nil {
+		return "One or more forms was empty. Make sure you give all forms.", nil
+	}
 
-	found := false
-	err = bot.DB.QueryRow(con, "select exists(select * from pronoun_msgs where subjective = $1 and objective = $2 and poss_det = $3 and poss_pro = $4 and reflexive = $5)", p[0], p[1], p[2], p[3], p[4]).Scan(&found)
+	exact, similar, err := bot.DB.SimilarPronouns(p)
 	if err != nil {
-		return bot.DB.InternalError(ctx, err)
+		return "", err
+	}
+	if exact != nil {
+		return fmt.Sprintf("The pronoun set **%v** already exists!", exact), nil
 	}
 
-	if found {
-		_, err = ctx.Send("That pronoun set has already been submitted!")
-		return
+	pending, err := bot.DB.PronounSubmissionPending(p)
+	if err != nil {
+		return "", err
+	}
+	if pending {
+		return "That pronoun set has already been submitted, and is waiting for review!", nil
 	}
 
-	msg, err := ctx.NewMessage().Channel(bot.Config.Bot.PronounChannel).
-		Embeds(discord.Embed{
-			Author: &discord.EmbedAuthor{
-				Name: fmt.Sprintf("%v#%v (%v)", ctx.Author.Username, ctx.Author.Discriminator, ctx.Author.ID),
-				Icon: ctx.Author.AvatarURL(),
-			},
-			Color:       db.EmbedColour,
-			Title:       "Pronoun submission",
-			Description: strings.Join(p, "/"),
-			Fields: []discord.EmbedField{{
-				Name:  "Submitted by",
-				Value: ctx.Author.Mention(),
-			}},
-			Timestamp: discord.NowTimestamp(),
-		}).Send()
+	sub, err := bot.DB.AddPronounSubmission(u.ID, p)
 	if err != nil {
-		return bot.DB.InternalError(ctx, err)
+		return "", err
 	}
 
-	con, cancel = bot.DB.Context()
-	defer cancel()
+	title := "Pronoun submission"
+	if l, ok := db.PronounLanguageByCode(p.Language); ok && l.Code != db.DefaultLanguage {
+		title += " (" + l.Name + ")"
+	}
 
-	_, err = bot.DB.Exec(con, "insert into pronoun_msgs (message_id, subjective, objective, poss_det, poss_pro, reflexive) values ($1, $2, $3, $4, $5, $6)", msg.ID, p[0], p[1], p[2], p[3], p[4])
-	if err == nil {
-		// if the error's non-nil, the message was still sent
-		// so don't just return immediately
-		ctx.State.React(msg.ChannelID, msg.ID, "✅")
-	} else {
-		log.Errorf("Error adding submission message %v to database: %v", msg.ID, err)
+	e := discord.Embed{
+		Author: &discord.EmbedAuthor{
+			Name: fmt.Sprintf("%v (%v)", u.Tag(), u.ID),
+			Icon: u.AvatarURL(),
+		},
+		Color:       db.EmbedColour,
+		Title:       title,
+		Description: p.String(),
+		Fields: []discord.EmbedField{{
+			Name:  "Submitted by",
+			Value: u.Mention(),
+		}},
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("Submission ID: %v", sub.ID),
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if len(similar) > 0 {
+		var b strings.Builder
+		for _, s := range similar {
+			fmt.Fprintf(&b, "`%v`: %v\n", s.ID, s)
+		}
+
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Possible duplicates",
+			Value: b.String(),
+		})
 	}
 
-	_, err = ctx.NewMessage().Content(
-		fmt.Sprintf("Successfully submitted the pronoun set **%v**.", strings.Join(p[:5], "/")),
-	).BlockMentions().Send()
+	msg, err := s.SendMessageComplex(bot.Config.Bot.PronounChannel, api.SendMessageData{
+		Embeds:     []discord.Embed{e},
+		Components: submissionButtons(sub.ID),
+	})
 	if err != nil {
-		bot.Report(ctx, err)
-		return err
+		return "", err
 	}
 
-	return
+	err = bot.DB.SetPronounSubmissionMessage(sub.ID, msg.ID)
+	if err != nil {
+		// the message was still sent, and the buttons don't depend on the message ID
+		// so don't just return immediately
+		log.Errorf("Error setting message for pronoun submission %v: %v", sub.ID, err)
+	}
+
+	return fmt.Sprintf("Successfully submitted the pronoun set **%v**! You'll get a DM once it's been reviewed.", p), nil
 }
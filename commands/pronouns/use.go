@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
 	"github.com/starshine-sys/bcr"
+	"github.com/termora/berry/commands/pronouns/examples"
 	"github.com/termora/berry/common/log"
 	"github.com/termora/berry/db"
 )
@@ -37,45 +38,39 @@ func (bot *Bot) use(ctx bcr.Contexter) (err error) {
 			fmt.Sprintf("You didn't give any pronouns to show! Try ``%vlist-pronouns`` for a list of all pronouns.", bot.Config.Bot.Prefixes[0]))
 	}
 
-	sets, err := bot.DB.GetPronoun(strings.Split(pronouns, "/")...)
+	lang, ok := db.PronounLanguageByCode(ctx.GetStringFlag("language"))
+	if !ok {
+		return unknownLanguage(ctx)
+	}
+
+	sets, mixed, err := bot.DB.ParsePronouns(lang.Code, pronouns)
 	if err != nil {
 		if errors.Cause(err) == pgx.ErrNoRows {
 			return ctx.SendEphemeral(
 				fmt.Sprintf("Couldn't find any pronoun sets from your input. Try `%vlist-pronouns` for a list of all pronouns; if it's not on there, feel free to submit it with `%vsubmit-pronouns`!", bot.Config.Bot.Prefixes[0], bot.Config.Bot.Prefixes[0]))
 		}
 		if err == db.ErrTooManyForms {
-			return ctx.SendEphemeral("You gave too many forms! Input up to five forms, separated with a slash (`/`).")
+			return ctx.SendEphemeral(fmt.Sprintf("You gave too many forms! Input up to %v forms, separated with a slash (`/`).", len(lang.Slots)))
 		}
 		return bot.DB.InternalError(ctx, err)
 	}
 
-	if len(sets) > 1 {
+	if len(sets) > 1 && !mixed {
 		if len(sets) > 25 {
 			return ctx.SendEphemeral("Found more than 25 sets matching your input! Please try again.")
 		}
 		return bot.pronounList(ctx, sets, name)
 	}
-	// use the first set
-	set := sets[0]
 
-	go bot.DB.IncrementPronounUse(set)
-
-	if tmplCount == 0 {
-		return ctx.SendEphemeral("There are no examples available for pronouns! If you think this is in error, please join the bot support server and ask there.")
+	for _, set := range sets {
+		go bot.DB.IncrementPronounUse(set)
 	}
 
-	useSet := &db.PronounSet{
-		Subjective: set.Subjective,
-		Objective:  set.Objective,
-		PossDet:    set.PossDet,
-		PossPro:    set.PossPro,
-		Reflexive:  set.Reflexive,
-	}
-	if name != "" {
-		useSet.Subjective = name
+	if examples.Count(lang.Code) == 0 {
+		return ctx.SendEphemeral("There are no examples available for pronouns! If you think this is in error, please join the bot support server and ask there.")
 	}
 
-	e, err := bot.pronounEmbeds(set, useSet)
+	e, err := bot.pronounEmbeds(sets, name)
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
@@ -88,35 +83,55 @@ func (bot *Bot) use(ctx bcr.Contexter) (err error) {
 	return
 }
 
-func (bot *Bot) pronounEmbeds(set, useSet *db.PronounSet) (e []discord.Embed, err error) {
-	var b strings.Builder
+// pronounEmbeds renders the given sets as paginated embeds.
+// Sets without an ID (custom pronouns) don't show one in the footer.
+func (bot *Bot) pronounEmbeds(sets []*db.PronounSet, name string) (e []discord.Embed, err error) {
+	pages, err := examples.Render(sets, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		title = examples.Title(sets)
+		desc  string
+		ids   []string
+	)
+	for _, set := range sets {
+		desc += fmt.Sprintf("**%s**\n", set)
+		if set.ID != 0 {
+			ids = append(ids, strconv.Itoa(set.ID))
+		}
+	}
+
+	footer := func(page int) string {
+		s := fmt.Sprintf("Page %v/%v", page, len(pages)+1)
+		if len(ids) > 0 {
+			s = fmt.Sprintf("ID: %v | %v", strings.Join(ids, ", "), s)
+		}
+		return s
+	}
 
 	e = append(e, discord.Embed{
-		Title:       fmt.Sprintf("%v/%v pronouns", set.Subjective, set.Objective),
-		Description: fmt.Sprintf("**%s**\n\nTo see these pronouns in action, use the arrow reactions on this message!", set),
+		Title:       title,
+		Description: desc + "\nTo see these pronouns in action, use the arrow reactions on this message!",
 		Color:       db.EmbedColour,
 		Footer: &discord.EmbedFooter{
-			Text: fmt.Sprintf("ID: %v | Page 1/%v", set.ID, tmplCount+1),
+			Text: footer(1),
 		},
 	})
 
-	for i := 0; i < tmplCount; i++ {
-		err = templates.ExecuteTemplate(&b, strconv.Itoa(i), useSet)
-		if err != nil {
-			return
-		}
+	for i, page := range pages {
 		e = append(e, discord.Embed{
-			Title:       fmt.Sprintf("%v/%v pronouns", set.Subjective, set.Objective),
-			Description: b.String(),
+			Title:       title,
+			Description: page,
 			Color:       db.EmbedColour,
 			Footer: &discord.EmbedFooter{
-				Text: fmt.Sprintf("ID: %v | Page %v/%v", set.ID, i+2, tmplCount+1),
+				Text: footer(i + 2),
 			},
 		})
-		b.Reset()
 	}
 
-	return e, err
+	return e, nil
 }
 
 func (bot *Bot) pronounList(ctx bcr.Contexter, sets []*db.PronounSet, name string) (err error) {
@@ -215,18 +230,7 @@ func (bot *Bot) pronounList(ctx bcr.Contexter, sets []*db.PronounSet, name strin
 
 	go bot.DB.IncrementPronounUse(set)
 
-	useSet := &db.PronounSet{
-		Subjective: set.Subjective,
-		Objective:  set.Objective,
-		PossDet:    set.PossDet,
-		PossPro:    set.PossPro,
-		Reflexive:  set.Reflexive,
-	}
-	if name != "" {
-		useSet.Subjective = name
-	}
-
-	e, err := bot.pronounEmbeds(set, useSet)
+	e, err := bot.pronounEmbeds([]*db.PronounSet{set}, name)
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
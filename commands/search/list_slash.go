@@ -69,11 +69,25 @@ func (bot *Bot) listPronounsSlash(ctx bcr.Contexter) error {
 		footerTmpl = "Sorting by # of uses | Page %v/%v"
 	}
 
-	p, err := bot.DB.Pronouns(order)
+	lang := ctx.GetStringFlag("language")
+	title := "List of pronouns (%v)"
+	if l, ok := db.PronounLanguageByCode(lang); ok && lang != "" {
+		title = "List of " + l.Name + " pronouns (%v)"
+	}
+
+	p, err := bot.DB.LanguagePronouns(lang, order)
 	if err != nil {
 		return bot.DB.InternalError(ctx, err)
 	}
 
+	var userCounts map[int]int64
+	if order == db.UsesPronounOrder {
+		userCounts, err = bot.DB.PronounUserCounts()
+		if err != nil {
+			return bot.DB.InternalError(ctx, err)
+		}
+	}
+
 	if order == db.RandomPronounOrder {
 		rand.Shuffle(len(p), func(i, j int) {
 			p[i], p[j] = p[j], p[i]
@@ -94,7 +108,11 @@ func (bot *Bot) listPronounsSlash(ctx bcr.Contexter) error {
 		}
 		b.WriteString(p.String())
 		if order == db.UsesPronounOrder {
-			b.WriteString(" (" + english.Plural(int(p.Uses), "use", "uses") + ")")
+			b.WriteString(" (" + english.Plural(int(p.Uses), "use", "uses"))
+			if n := userCounts[p.ID]; n > 0 {
+				b.WriteString(", " + english.Plural(int(n), "user", "users"))
+			}
+			b.WriteString(")")
 		}
 		b.WriteRune('\n')
 		count++
@@ -104,7 +122,7 @@ func (bot *Bot) listPronounsSlash(ctx bcr.Contexter) error {
 	e := make([]discord.Embed, 0)
 	for i, page := range s {
 		e = append(e, discord.Embed{
-			Title:       fmt.Sprintf("List of pronouns (%v)", len(p)),
+			Title:       fmt.Sprintf(title, len(p)),
 			Description: page,
 			Color:       db.EmbedColour,
 			Footer: &discord.EmbedFooter{
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/bot"
+	"github.com/termora/berry/db"
 )
 
 type Bot struct {
@@ -237,6 +238,12 @@ func Init(b *bot.Bot) (m string, list []*bcr.Command) {
 							{Name: "Sort by number of uses", Value: "uses"},
 						},
 					},
+					&discord.StringOption{
+						OptionName:  "language",
+						Description: "Only show pronouns in this language",
+						Required:    false,
+						Choices:     db.PronounLanguageChoices(),
+					},
 				},
 			},
 		},
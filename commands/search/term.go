@@ -33,16 +33,16 @@ func (bot *Bot) term(ctx *bcr.Context) (err error) {
 		}
 		exact = true
 	} else {
-		terms, err := bot.DB.TermName(ctx.RawArgs)
-		if err != nil {
+		m, err := bot.DB.TermByName(ctx.RawArgs)
+		if err != nil && errors.Cause(err) != pgx.ErrNoRows {
 			return bot.DB.InternalError(ctx, err)
-		} else if err == nil && len(terms) > 0 {
-			if len(terms) > 1 {
+		} else if err == nil {
+			if m.Ambiguous {
 				return bot.search(ctx)
 			}
 
 			exact = true
-			term = terms[0]
+			term = m.Term
 			goto found
 		}
 
@@ -96,16 +96,16 @@ func (bot *Bot) termSlash(ctx bcr.Contexter) (err error) {
 		}
 		exact = true
 	} else {
-		terms, err := bot.DB.TermName(query)
-		if err != nil {
+		m, err := bot.DB.TermByName(query)
+		if err != nil && errors.Cause(err) != pgx.ErrNoRows {
 			return bot.DB.InternalError(ctx, err)
-		} else if err == nil && len(terms) > 0 {
-			if len(terms) > 1 {
+		} else if err == nil {
+			if m.Ambiguous {
 				return bot.searchSlash(ctx)
 			}
 
 			exact = true
-			term = terms[0]
+			term = m.Term
 			goto found
 		}
 
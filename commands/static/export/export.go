@@ -7,7 +7,7 @@ import (
 )
 
 // ExportVersion is the current version
-const ExportVersion = 3
+const ExportVersion = 4
 
 // Export is an export of the database
 type Export struct {
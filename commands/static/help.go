@@ -94,7 +94,7 @@ func (bot *Bot) help(ctx bcr.Contexter) (err error) {
 			},
 			{
 				Name:  "Pronouns",
-				Value: fmt.Sprintf("`pronouns`: see how pronouns are used in a sentence! (optionally with your name)\n`pronouns list`: list all pronouns known to %v!\n`pronouns submit`: submit a pronoun set to be added!", bot.Router.Bot.Username),
+				Value: fmt.Sprintf("`pronouns`: see how pronouns are used in a sentence! (optionally with your name)\n`pronouns list`: list all pronouns known to %v!\n`pronouns submit`: submit a pronoun set to be added!\n`pronouns save`: save pronouns to your profile, so others can see them with `pronouns user`!\n`pronouns pluralkit`: show a PluralKit member's pronouns!", bot.Router.Bot.Username),
 			},
 			{
 				Name:  "For staff",
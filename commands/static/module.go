@@ -10,6 +10,7 @@ import (
 	"github.com/starshine-sys/bcr"
 	"github.com/termora/berry/bot"
 	"github.com/termora/berry/bot/cc"
+	"github.com/termora/berry/db"
 )
 
 // Commands ...
@@ -54,6 +55,14 @@ func Init(b *bot.Bot) (m string, o []*bcr.Command) {
 				Cooldown:      1 * time.Second,
 				Blacklistable: true,
 				SlashCommand:  bot.submitPronouns,
+				Options: &[]discord.CommandOption{
+					&discord.StringOption{
+						OptionName:  "language",
+						Description: "The language of the pronouns (defaults to English)",
+						Required:    false,
+						Choices:     db.PronounLanguageChoices(),
+					},
+				},
 			},
 		},
 	}
@@ -59,8 +59,6 @@ func (bot *Bot) interactionCreate(ic *gateway.InteractionCreateEvent) {
 	case "submit-feedback-modal":
 		err = bot.handleFeedback(ic, data)
 	case "submit-term-modal":
-	case "submit-pronouns-modal":
-		err = bot.handlePronouns(ic, data)
 	}
 	if err != nil {
 		log.Errorf("handling modal interaction: %v", err)
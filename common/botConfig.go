@@ -77,6 +77,9 @@ type BotConfig struct {
 	QuickNotes map[string]string `toml:"quick_notes"`
 
 	ContributorRoles []ContributorRole `toml:"contributor_roles"`
+
+	// PluralKitURL is the base URL for the PluralKit API, defaults to the public API if not set
+	PluralKitURL string `toml:"pluralkit_url"`
 }
 
 // ContributorRole ...
@@ -72,6 +72,8 @@ type SiteConfig struct {
 	Contact  bool   `toml:"contact"`
 	// Optional description shown in embeds, when not linking to a term page
 	Description string `toml:"description"`
+	// Optional root URL of the API, used by embed.js for hover cards, such as https://api.termora.org
+	APIURL string `toml:"api_url"`
 
 	Plausible struct {
 		Domain string `toml:"domain"`
@@ -83,4 +85,11 @@ type SiteConfig struct {
 
 type APIConfig struct {
 	Port string `toml:"port"`
+
+	// Requests per minute, for anonymous requests (per IP) and API keys without their own limit
+	AnonymousRateLimit int `toml:"anonymous_rate_limit"`
+	KeyRateLimit       int `toml:"key_rate_limit"`
+
+	// Whether to trust X-Forwarded-For and X-Real-IP, only set this if the API is behind a reverse proxy
+	BehindProxy bool `toml:"behind_proxy"`
 }
@@ -0,0 +1,76 @@
+// Package httpcache answers conditional requests for pages that only change when terms do.
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// Middleware sets ETag and Last-Modified headers from the terms version,
+// and answers If-None-Match and If-Modified-Since with 304 Not Modified if terms haven't changed since.
+// If vary isn't nil, its return value is added to the ETag, for responses that also depend on something in the request.
+func Middleware(d *db.DB, vary func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			v, err := d.TermsVersion()
+			if err != nil {
+				// not being able to cache isn't fatal, the handler can still try to respond
+				log.Errorf("Error getting terms version: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tag := strconv.FormatInt(v.Change, 10) + "-" + strconv.FormatInt(v.Categories, 36) + "-" + strconv.FormatInt(v.Tags, 36)
+			if vary != nil {
+				if s := vary(r); s != "" {
+					tag += "-" + s
+				}
+			}
+			etag := `W/"` + tag + `"`
+			modified := v.LastModified.UTC().Truncate(time.Second)
+
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+			if notModified(r, etag, modified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// notModified checks the request's conditional headers.
+// If-None-Match takes precedence over If-Modified-Since, as in RFC 7232.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !modified.After(t)
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// Package staff checks users' current roles in the support server.
+// API keys and dashboard sessions keep the scope they were created with, so this limits them to what their owner can still do.
+package staff
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ReneKroon/ttlcache/v2"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/db"
+)
+
+// Checker looks up users' roles in the support server over the REST API
+type Checker struct {
+	client  *api.Client
+	guildID discord.GuildID
+	conf    common.BotConfig
+
+	cache *ttlcache.Cache
+}
+
+// New returns a Checker using the support server bot's token, or the main bot's token if that isn't set.
+// It returns nil if there's no support server or token configured, in which case scopes aren't limited.
+func New(c common.Config) *Checker {
+	token := c.Bot.SupportToken
+	if token == "" {
+		token = c.Bot.Token
+	}
+	if token == "" || !c.Bot.SupportGuildID.IsValid() {
+		return nil
+	}
+
+	cache := ttlcache.NewCache()
+	// so a removed role stops working about as quickly as a revoked API key
+	cache.SetTTL(time.Minute)
+	cache.SkipTTLExtensionOnHit(true)
+	cache.SetCacheSizeLimit(1000)
+
+	return &Checker{
+		client:  api.NewClient("Bot " + token),
+		guildID: c.Bot.SupportGuildID,
+		conf:    c.Bot,
+		cache:   cache,
+	}
+}
+
+// Scope returns the highest scope the user currently has.
+// Users who aren't in the support server only have the read scope, unless they're a bot owner.
+func (c *Checker) Scope(userID discord.UserID) (db.APIScope, error) {
+	if v, err := c.cache.Get(userID.String()); err == nil {
+		return v.(db.APIScope), nil
+	}
+
+	var roles []discord.RoleID
+	m, err := c.client.Member(c.guildID, userID)
+	if err != nil {
+		var httpErr *httputil.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != http.StatusNotFound {
+			return "", err
+		}
+	} else {
+		roles = m.RoleIDs
+	}
+
+	scope := db.StaffScope(c.conf, userID, roles)
+	c.cache.Set(userID.String(), scope)
+	return scope, nil
+}
+
+// Limit returns scope limited to the user's current scope.
+// If c is nil, scope is returned unchanged.
+func (c *Checker) Limit(userID discord.UserID, scope db.APIScope) (db.APIScope, error) {
+	if c == nil {
+		return scope, nil
+	}
+
+	current, err := c.Scope(userID)
+	if err != nil {
+		return "", err
+	}
+	return scope.Min(current), nil
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/termora/berry/common"
+)
+
+// ErrInvalidScope is returned when creating a key with an unknown scope
+var ErrInvalidScope = errors.New("invalid API key scope")
+
+// APIKeyPrefix is prepended to all API keys, so they're easy to recognise
+const APIKeyPrefix = "trm_"
+
+// APIScope is what an API key is allowed to do. Each scope includes the ones before it.
+type APIScope string
+
+// API key scopes, matching the bot's permission levels
+const (
+	ScopeRead     APIScope = "read"
+	ScopeDirector APIScope = "director"
+	ScopeAdmin    APIScope = "admin"
+)
+
+// Has returns true if the scope includes the other scope
+func (s APIScope) Has(other APIScope) bool {
+	return s.level() >= other.level()
+}
+
+func (s APIScope) level() int {
+	switch s {
+	case ScopeDirector:
+		return 1
+	case ScopeAdmin:
+		return 2
+	}
+	return 0
+}
+
+// Valid returns true if this is a known scope
+func (s APIScope) Valid() bool {
+	return s == ScopeRead || s == ScopeDirector || s == ScopeAdmin
+}
+
+// Min returns the lower of the two scopes
+func (s APIScope) Min(other APIScope) APIScope {
+	if s.Has(other) {
+		return other
+	}
+	return s
+}
+
+// StaffScope returns the highest scope a user with the given roles can have:
+// admin for bot owners and admins, director for directors, and read for everyone else.
+func StaffScope(c common.BotConfig, userID discord.UserID, roles []discord.RoleID) APIScope {
+	for _, u := range c.BotOwners {
+		if u == userID {
+			return ScopeAdmin
+		}
+	}
+
+	scope := ScopeRead
+	for _, r := range roles {
+		for _, a := range c.Admins {
+			if r == a {
+				return ScopeAdmin
+			}
+		}
+
+		for _, d := range c.Directors {
+			if r == d {
+				scope = ScopeDirector
+			}
+		}
+	}
+	return scope
+}
+
+// APIKey is a key for the API. The key itself isn't stored, only its hash.
+type APIKey struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+
+	KeyHash string `json:"-"`
+
+	CreatedBy discord.UserID `json:"created_by"`
+	Created   time.Time      `json:"created"`
+	LastUsed  *time.Time     `json:"last_used,omitempty"`
+	Revoked   *time.Time     `json:"revoked,omitempty"`
+
+	// RateLimit is the number of requests per minute, nil for the default
+	RateLimit *int `json:"rate_limit,omitempty"`
+
+	// Scope is what the key can do. Changes made with a key are attributed to CreatedBy.
+	Scope APIScope `json:"scope"`
+}
+
+// APIUsage is the number of requests made to an endpoint on a single day.
+type APIUsage struct {
+	KeyID    *int      `json:"key_id"`
+	Endpoint string    `json:"endpoint"`
+	Day      time.Time `json:"day"`
+	Count    int64     `json:"count"`
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey creates a new API key. The returned token is the only time the key is available.
+func (db *DB) CreateAPIKey(name string, createdBy discord.UserID, scope APIScope, rateLimit *int) (key APIKey, token string, err error) {
+	if !scope.Valid() {
+		return key, "", ErrInvalidScope
+	}
+
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return key, "", err
+	}
+	token = APIKeyPrefix + hex.EncodeToString(b)
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Creating API key %q for %v", name, createdBy)
+
+	err = pgxscan.Get(ctx, db, &key, `insert into api_keys (name, prefix, key_hash, created_by, rate_limit, scope)
+	values ($1, $2, $3, $4, $5, $6) returning *`, name, token[:len(APIKeyPrefix)+6], hashAPIKey(token), createdBy, rateLimit, scope)
+	return key, token, err
+}
+
+// APIKeyByToken returns the (non-revoked) API key for the given token.
+func (db *DB) APIKeyByToken(token string) (key APIKey, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Get(ctx, db, &key, "select * from api_keys where key_hash = $1 and revoked is null", hashAPIKey(strings.TrimSpace(token)))
+	return
+}
+
+// APIKeys returns all API keys, including revoked ones.
+func (db *DB) APIKeys() (keys []APIKey, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db, &keys, "select * from api_keys order by id")
+	return
+}
+
+// RevokeAPIKey revokes an API key, returning false if the key doesn't exist or was already revoked.
+func (db *DB) RevokeAPIKey(id int) (revoked bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Revoking API key %v", id)
+
+	ct, err := db.Exec(ctx, "update api_keys set revoked = (current_timestamp at time zone 'utc') where id = $1 and revoked is null", id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() != 0, nil
+}
+
+// AddAPIUsage adds n requests to today's usage for the given key (nil for anonymous requests) and endpoint.
+func (db *DB) AddAPIUsage(keyID *int, endpoint string, n int64) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, `insert into api_usage (key_id, endpoint, count) values ($1, $2, $3)
+	on conflict (coalesce(key_id, 0), endpoint, day) do update set count = api_usage.count + $3`, keyID, endpoint, n)
+	if err != nil || keyID == nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, "update api_keys set last_used = (current_timestamp at time zone 'utc') where id = $1", *keyID)
+	return err
+}
+
+// APIKeyUsage returns the usage of a key since the given time, ordered by day and endpoint.
+func (db *DB) APIKeyUsage(keyID int, since time.Time) (usage []APIUsage, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db, &usage, `select * from api_usage
+	where key_id = $1 and day >= $2::date order by day desc, count desc`, keyID, since.UTC())
+	return
+}
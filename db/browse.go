@@ -0,0 +1,167 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/termora/berry/db/search"
+)
+
+// BrowseLetters are the letters terms are listed under when browsing alphabetically.
+// Terms starting with anything other than A-Z are listed under #.
+var BrowseLetters = []string{"#", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
+
+// TermLetter returns the letter the term is listed under
+func TermLetter(t *Term) string {
+	for _, r := range t.Name {
+		r = unicode.ToUpper(r)
+		if r >= 'A' && r <= 'Z' {
+			return string(r)
+		}
+		return "#"
+	}
+	return "#"
+}
+
+// LetterSlug returns the URL path segment for a letter, as # can't be used in paths
+func LetterSlug(letter string) string {
+	if letter == "#" {
+		return "other"
+	}
+	return strings.ToLower(letter)
+}
+
+// LetterFromSlug is the inverse of LetterSlug. ok is false if the slug isn't one of BrowseLetters.
+func LetterFromSlug(slug string) (letter string, ok bool) {
+	for _, l := range BrowseLetters {
+		if LetterSlug(l) == strings.ToLower(slug) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// LetterTerms returns all terms listed under the given letter, from the term cache.
+// Terms hidden from lists are excluded.
+func (db *DB) LetterTerms(letter string) (terms []*Term, err error) {
+	all, err := db.CachedTerms(search.FlagListHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range all {
+		if TermLetter(t) == letter {
+			terms = append(terms, t)
+		}
+	}
+	return terms, nil
+}
+
+// CategoryCount is a category with the number of terms listed in it
+type CategoryCount struct {
+	Category
+	Count int
+}
+
+// CategoryCounts returns all categories with at least one listed term, and how many terms they have, from the term cache.
+// The counts are only computed once for every terms version.
+func (db *DB) CategoryCounts() ([]CategoryCount, error) {
+	db.termCache.mu.Lock()
+	defer db.termCache.mu.Unlock()
+
+	terms, err := db.cachedTerms()
+	if err != nil {
+		return nil, err
+	}
+
+	if db.termCache.categories == nil {
+		c := []CategoryCount{}
+		idx := map[int]int{}
+		for _, t := range terms {
+			if t.Flags&search.FlagListHidden != 0 {
+				continue
+			}
+
+			i, ok := idx[t.Category]
+			if !ok {
+				i = len(c)
+				idx[t.Category] = i
+				c = append(c, CategoryCount{Category: Category{ID: t.Category, Name: t.CategoryName}})
+			}
+			c[i].Count++
+		}
+
+		sort.Slice(c, func(i, j int) bool { return c[i].ID < c[j].ID })
+		db.termCache.categories = c
+	}
+
+	// callers can modify the returned slice
+	c := make([]CategoryCount, len(db.termCache.categories))
+	copy(c, db.termCache.categories)
+	return c, nil
+}
+
+// RecentTerms returns the n most recently added and the n most recently updated terms, from the term cache.
+// Terms hidden from lists are excluded, and terms that haven't been updated since they were added are only in added.
+func (db *DB) RecentTerms(n int) (added, updated []*Term, err error) {
+	terms, err := db.CachedTerms(search.FlagListHidden)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added = make([]*Term, len(terms))
+	copy(added, terms)
+	sort.SliceStable(added, func(i, j int) bool { return added[i].Created.After(added[j].Created) })
+	if len(added) > n {
+		added = added[:n]
+	}
+
+	for _, t := range terms {
+		if t.LastModified.After(t.Created) {
+			updated = append(updated, t)
+		}
+	}
+	sort.SliceStable(updated, func(i, j int) bool { return updated[i].LastModified.After(updated[j].LastModified) })
+	if len(updated) > n {
+		updated = updated[:n]
+	}
+	return added, updated, nil
+}
+
+// CategoryTerms returns the terms listed in a category, from the term cache
+func (db *DB) CategoryTerms(id int) (terms []*Term, err error) {
+	all, err := db.CachedTerms(search.FlagListHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range all {
+		if t.Category == id {
+			terms = append(terms, t)
+		}
+	}
+	return terms, nil
+}
+
+// BrowsePageSize is the number of terms shown per page when browsing
+const BrowsePageSize = 50
+
+// Paginate returns the terms on the given page (starting at 1) and the total number of pages.
+// Pages out of range return no terms.
+func Paginate(terms []*Term, page, perPage int) (pageTerms []*Term, pages int) {
+	pages = (len(terms) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	if page < 1 || page > pages {
+		return nil, pages
+	}
+
+	end := page * perPage
+	if end > len(terms) {
+		end = len(terms)
+	}
+	return terms[(page-1)*perPage : end], pages
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"sync"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/termora/berry/db/search"
+)
+
+// TermCacheInterval is how often the term cache checks if terms have changed
+var TermCacheInterval = time.Second
+
+// TermsVersion identifies the current state of all terms.
+// It changes whenever a term is created, updated, or deleted.
+type TermsVersion struct {
+	// Change is the ID of the latest change to a term
+	Change int64
+	// LastModified is the latest of any term's last_modified and the latest change
+	LastModified time.Time
+	// Categories is a hash of category names, as terms include their category's name
+	Categories int64
+	// Tags is a hash of tag display names, as terms include their tags' display names
+	Tags int64
+}
+
+// termCache caches all terms, and is invalidated whenever the terms version changes
+type termCache struct {
+	mu      sync.Mutex
+	checked time.Time
+	version TermsVersion
+
+	loaded bool
+	terms  []*Term
+	byID   map[int]*Term
+	// categories is computed from terms the first time it's needed, see CategoryCounts
+	categories []CategoryCount
+}
+
+// InvalidateTerms makes the next cached read check if terms have changed
+func (db *DB) InvalidateTerms() {
+	db.termCache.mu.Lock()
+	db.termCache.checked = time.Time{}
+	db.termCache.mu.Unlock()
+}
+
+// TermsVersion returns the current terms version, checking the database at most once every TermCacheInterval
+func (db *DB) TermsVersion() (v TermsVersion, err error) {
+	c := &db.termCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = db.checkTermsVersion()
+	return c.version, err
+}
+
+// checkTermsVersion updates the cached version, dropping cached terms if it changed.
+// c.mu must be held.
+func (db *DB) checkTermsVersion() error {
+	c := &db.termCache
+
+	// while listening for changes the cache is invalidated when terms change,
+	// so this is only a fallback in case a notification is missed
+	interval := TermCacheInterval
+	if db.listening() {
+		interval = time.Minute
+	}
+	if time.Since(c.checked) < interval {
+		return nil
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	var v TermsVersion
+	err := db.QueryRow(ctx, `select
+	coalesce((select id from changes where subject = 'term' order by id desc limit 1), 0),
+	greatest(
+		(select timestamp from changes where subject = 'term' order by id desc limit 1),
+		(select max(last_modified) from terms),
+		'epoch'
+	),
+	(select coalesce(sum(hashtext(id || name)), 0) from categories),
+	(select coalesce(sum(hashtext(normalized || display)), 0) from tags)`).Scan(&v.Change, &v.LastModified, &v.Categories, &v.Tags)
+	if err != nil {
+		return err
+	}
+
+	if v != c.version {
+		Debug("Terms changed (change %v), clearing cache", v.Change)
+		c.version = v
+		c.loaded = false
+		c.terms, c.byID = nil, nil
+		c.categories = nil
+	}
+	c.checked = time.Now()
+	return nil
+}
+
+// cachedTerms returns all cached terms, loading them if needed.
+// c.mu must be held.
+func (db *DB) cachedTerms() ([]*Term, error) {
+	c := &db.termCache
+	if err := db.checkTermsVersion(); err != nil {
+		return nil, err
+	}
+	if c.loaded {
+		return c.terms, nil
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Loading all terms into cache")
+
+	var terms []*Term
+	err := pgxscan.Select(ctx, db.Pool, &terms, `select
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
+	array(select display from public.tags where normalized = any(t.tags)) as display_tags
+	from public.terms as t, public.categories as c
+	where t.category = c.id
+	order by t.name, t.id`)
+	if err != nil {
+		return nil, err
+	}
+
+	c.terms = terms
+	c.byID = make(map[int]*Term, len(terms))
+	for _, t := range terms {
+		c.byID[t.ID] = t
+	}
+	c.loaded = true
+	return terms, nil
+}
+
+// CachedTerms is like GetTerms, but uses the term cache.
+// The returned terms are copies, so they can be modified.
+func (db *DB) CachedTerms(mask search.TermFlag) (terms []*Term, err error) {
+	db.termCache.mu.Lock()
+	defer db.termCache.mu.Unlock()
+
+	all, err := db.cachedTerms()
+	if err != nil {
+		return nil, err
+	}
+
+	terms = make([]*Term, 0, len(all))
+	for _, t := range all {
+		if t.Flags&mask == 0 {
+			c := *t
+			terms = append(terms, &c)
+		}
+	}
+	return terms, nil
+}
+
+// CachedTerm is like GetTerm, but uses the term cache.
+// Returns pgx.ErrNoRows if the term doesn't exist.
+func (db *DB) CachedTerm(id int) (*Term, error) {
+	db.termCache.mu.Lock()
+	defer db.termCache.mu.Unlock()
+
+	if _, err := db.cachedTerms(); err != nil {
+		return nil, err
+	}
+
+	t, ok := db.termCache.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	c := *t
+	return &c, nil
+}
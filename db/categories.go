@@ -1,5 +1,7 @@
 package db
 
+import "errors"
+
 // AddCategory ...
 func (db *DB) AddCategory(name string) (id int, err error) {
 	ctx, cancel := db.Context()
@@ -12,3 +14,49 @@ func (db *DB) AddCategory(name string) (id int, err error) {
 	Debug("Added category %v", id)
 	return
 }
+
+// ErrCategoryNotEmpty is returned when trying to remove a category that still has terms
+var ErrCategoryNotEmpty = errors.New("category still has terms")
+
+// RenameCategory renames a category
+func (db *DB) RenameCategory(id int, name string) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Renaming category %v to %v", id, name)
+
+	ct, err := db.Exec(ctx, "update public.categories set name = $1 where id = $2", name, id)
+	if err != nil {
+		return
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return
+}
+
+// RemoveCategory removes a category. Terms are deleted along with their category, so this refuses to remove categories that aren't empty.
+func (db *DB) RemoveCategory(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Removing category %v", id)
+
+	var exists bool
+	err = db.QueryRow(ctx, "select exists (select from public.terms where category = $1)", id).Scan(&exists)
+	if err != nil {
+		return
+	}
+	if exists {
+		return ErrCategoryNotEmpty
+	}
+
+	ct, err := db.Exec(ctx, "delete from public.categories where id = $1", id)
+	if err != nil {
+		return
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrInvalidCursor is returned by Changes if the cursor isn't a change ID
+var ErrInvalidCursor = errors.New("invalid change cursor")
+
+// Change is a single entry in the change log
+type Change struct {
+	ID        int64     `json:"-"`
+	Subject   string    `json:"subject"`
+	SubjectID int       `json:"id"`
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Changes returns up to limit changes after the given change ID, oldest first.
+//
+// Change IDs are assigned before a transaction commits, so a change can be committed after one with a higher ID has already been returned.
+// To never skip over those, changes are ordered by the ID of the transaction that made them,
+// and only returned once every transaction that started before theirs has finished.
+func (db *DB) Changes(since int64, limit int) (c []Change, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting %v changes since %v", limit, since)
+
+	var txid int64
+	if since != 0 {
+		err = db.QueryRow(ctx, "select txid from changes where id = $1", since).Scan(&txid)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInvalidCursor
+			}
+			return nil, err
+		}
+	}
+
+	err = pgxscan.Select(ctx, db.Pool, &c, `select id, subject::text, subject_id, action::text, timestamp from changes
+	where (txid, id) > ($1, $2) and txid < txid_snapshot_xmin(txid_current_snapshot())
+	order by txid, id limit $3`, txid, since, limit)
+	return c, err
+}
+
+// TermsByID returns all terms with the given IDs
+func (db *DB) TermsByID(ids []int) (terms []*Term, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting terms %v", ids)
+
+	err = pgxscan.Select(ctx, db.Pool, &terms, `select
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags, t.image_url,
+	array(select display from public.tags where normalized = any(t.tags)) as display_tags
+	from public.terms as t, public.categories as c where t.id = any($1) and t.category = c.id`, ids)
+	return terms, err
+}
+
+// ExplanationsByID returns all explanations with the given IDs
+func (db *DB) ExplanationsByID(ids []int) (e []*Explanation, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting explanations %v", ids)
+
+	err = pgxscan.Select(ctx, db.Pool, &e, "select id, name, aliases, description, created, as_command from public.explanations where id = any($1)", ids)
+	return e, err
+}
+
+// PronounSetsByID returns all pronoun sets with the given IDs
+func (db *DB) PronounSetsByID(ids []int) (p []*PronounSet, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting pronoun sets %v", ids)
+
+	err = pgxscan.Select(ctx, db.Pool, &p, "select "+pronounColumns+", uses from pronouns where id = any($1)", ids)
+	return p, err
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// latestChange returns the cursor after every change that's currently visible
+func latestChange(t *testing.T, db *DB) (cursor int64) {
+	t.Helper()
+
+	for {
+		c, err := db.Changes(cursor, 500)
+		if err != nil {
+			t.Fatalf("getting changes: %v", err)
+		}
+		if len(c) == 0 {
+			return cursor
+		}
+		cursor = c[len(c)-1].ID
+	}
+}
+
+// TestChangesHeldOpenTransaction checks that a change committed after one with a higher ID isn't skipped
+func TestChangesHeldOpenTransaction(t *testing.T) {
+	db := testDB(t)
+	cursor := latestChange(t, db)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	insert := `insert into pronouns (language, forms, subjective, objective, poss_det, poss_pro, reflexive)
+	values ('en', $1, $2, $3, $4, $5, $6) returning id`
+	forms := func(name string) []interface{} {
+		f := []string{name + "a", name + "b", name + "c", name + "d", name + "e"}
+		return []interface{}{f, f[0], f[1], f[2], f[3], f[4]}
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	// this transaction writes first, so its change gets the lower ID, but it commits last
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var slow, fast int
+	err = tx.QueryRow(ctx, insert, forms("slow"+suffix)...).Scan(&slow)
+	if err != nil {
+		t.Fatalf("inserting in transaction: %v", err)
+	}
+	err = db.QueryRow(ctx, insert, forms("fast"+suffix)...).Scan(&fast)
+	if err != nil {
+		t.Fatalf("inserting outside transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := db.Context()
+		defer cancel()
+		db.Exec(ctx, "delete from pronouns where id = any($1)", []int{slow, fast})
+	})
+
+	c, err := db.Changes(cursor, 500)
+	if err != nil {
+		t.Fatalf("getting changes: %v", err)
+	}
+	for _, ch := range c {
+		if ch.Subject == "pronouns" && ch.SubjectID == fast {
+			t.Fatalf("change %v was returned while an earlier transaction was still open", ch.ID)
+		}
+	}
+	if len(c) > 0 {
+		cursor = c[len(c)-1].ID
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		t.Fatalf("committing: %v", err)
+	}
+
+	c, err = db.Changes(cursor, 500)
+	if err != nil {
+		t.Fatalf("getting changes: %v", err)
+	}
+
+	var got []int
+	for _, ch := range c {
+		if ch.Subject == "pronouns" && (ch.SubjectID == slow || ch.SubjectID == fast) {
+			got = append(got, ch.SubjectID)
+		}
+	}
+	if len(got) != 2 || got[0] != slow || got[1] != fast {
+		t.Fatalf("expected changes for %v then %v after the transaction committed, got %v", slow, fast, got)
+	}
+}
+
+func TestChangesInvalidCursor(t *testing.T) {
+	db := testDB(t)
+
+	_, err := db.Changes(-1, 1)
+	if err != ErrInvalidCursor {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
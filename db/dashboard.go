@@ -0,0 +1,150 @@
+package db
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+)
+
+// How long dashboard login links and sessions are valid for
+const (
+	DashboardLoginExpiry   = 15 * time.Minute
+	DashboardSessionExpiry = 24 * time.Hour
+)
+
+// DashboardSession is a logged in session on the website's dashboard.
+// Like API keys, only a hash of the session token is stored.
+type DashboardSession struct {
+	TokenHash string
+
+	UserID discord.UserID
+	// Name is the user's Discord tag when they logged in
+	Name string
+	// Scope is either ScopeDirector or ScopeAdmin, depending on the user's roles when they logged in.
+	// The website limits it to the user's current roles on every request.
+	Scope APIScope
+	// CSRFToken must be sent with every form on the dashboard
+	CSRFToken string
+
+	Created time.Time
+	Expires time.Time
+}
+
+// RandomToken returns a random hex token, for use in session tokens and similar
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateDashboardLogin creates a one-time login link token for the given user.
+// The returned token is the only time it's available.
+func (db *DB) CreateDashboardLogin(userID discord.UserID, name string, scope APIScope) (token string, err error) {
+	if scope != ScopeDirector && scope != ScopeAdmin {
+		return "", ErrInvalidScope
+	}
+
+	token, err = RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Creating dashboard login for %v", userID)
+
+	// expired logins and sessions are useless, so this is as good a time as any to clean them up
+	_, err = db.Exec(ctx, "delete from dashboard_logins where expires < (current_timestamp at time zone 'utc')")
+	if err != nil {
+		return "", err
+	}
+	_, err = db.Exec(ctx, "delete from dashboard_sessions where expires < (current_timestamp at time zone 'utc')")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = db.Exec(ctx, `insert into dashboard_logins (token_hash, user_id, name, scope, expires)
+	values ($1, $2, $3, $4, $5)`, hashAPIKey(token), userID, name, scope, time.Now().UTC().Add(DashboardLoginExpiry))
+	return token, err
+}
+
+// DashboardLoginValid returns true if the login token exists and hasn't expired or been used yet.
+func (db *DB) DashboardLoginValid(token string) (valid bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = db.QueryRow(ctx, `select exists(select * from dashboard_logins
+	where token_hash = $1 and expires > (current_timestamp at time zone 'utc'))`, hashAPIKey(strings.TrimSpace(token))).Scan(&valid)
+	return
+}
+
+// UseDashboardLogin exchanges a login token for a new session, and returns the session token.
+// Each login token can only be used once. Returns pgx.ErrNoRows if the token doesn't exist or has expired.
+func (db *DB) UseDashboardLogin(token string) (s DashboardSession, sessionToken string, err error) {
+	sessionToken, err = RandomToken()
+	if err != nil {
+		return s, "", err
+	}
+	csrf, err := RandomToken()
+	if err != nil {
+		return s, "", err
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return s, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		userID discord.UserID
+		name   string
+		scope  APIScope
+	)
+	err = tx.QueryRow(ctx, `delete from dashboard_logins
+	where token_hash = $1 and expires > (current_timestamp at time zone 'utc')
+	returning user_id, name, scope`, hashAPIKey(strings.TrimSpace(token))).Scan(&userID, &name, &scope)
+	if err != nil {
+		return s, "", err
+	}
+
+	Debug("Starting dashboard session for %v", userID)
+
+	err = pgxscan.Get(ctx, tx, &s, `insert into dashboard_sessions (token_hash, user_id, name, scope, csrf_token, expires)
+	values ($1, $2, $3, $4, $5, $6) returning *`, hashAPIKey(sessionToken), userID, name, scope, csrf, time.Now().UTC().Add(DashboardSessionExpiry))
+	if err != nil {
+		return s, "", err
+	}
+
+	return s, sessionToken, tx.Commit(ctx)
+}
+
+// DashboardSession returns the (non-expired) session for the given session token.
+func (db *DB) DashboardSession(token string) (s DashboardSession, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Get(ctx, db, &s, `select * from dashboard_sessions
+	where token_hash = $1 and expires > (current_timestamp at time zone 'utc')`, hashAPIKey(token))
+	return
+}
+
+// EndDashboardSession logs out a dashboard session.
+func (db *DB) EndDashboardSession(token string) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, "delete from dashboard_sessions where token_hash = $1", hashAPIKey(token))
+	return
+}
@@ -47,6 +47,9 @@ type DB struct {
 	TermBaseURL string
 
 	IncFunc func()
+
+	termCache termCache
+	listener  listener
 }
 
 // Init ...
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"os"
+	"testing"
+)
+
+// testDB connects to the database in TERMORA_TEST_DATABASE, skipping the test if it isn't set.
+// Tests using it make changes to the database, so don't point it at one that's in use.
+func testDB(t *testing.T) *DB {
+	t.Helper()
+
+	url := os.Getenv("TERMORA_TEST_DATABASE")
+	if url == "" {
+		t.Skip("TERMORA_TEST_DATABASE isn't set")
+	}
+
+	db, err := Init(url)
+	if err != nil {
+		t.Fatalf("connecting to database: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
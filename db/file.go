@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/starshine-sys/snowflake/v2"
@@ -27,6 +28,26 @@ func (f File) URL() string {
 	return f.url
 }
 
+// MaxFileSize is the maximum size of an uploaded file, in bytes
+const MaxFileSize = 1 * 1024 * 1024
+
+// ImageContentType returns the content type of an image file from its name, or "unknown" if it isn't an image
+func ImageContentType(filename string) string {
+	filename = strings.ToLower(filename)
+
+	switch {
+	case strings.HasSuffix(filename, ".jpg"), strings.HasSuffix(filename, ".jpeg"):
+		return "image/jpeg"
+	case strings.HasSuffix(filename, ".png"):
+		return "image/png"
+	case strings.HasSuffix(filename, ".gif"):
+		return "image/gif"
+	case strings.HasSuffix(filename, ".webp"):
+		return "image/webp"
+	}
+	return "unknown"
+}
+
 // AddFile adds a file
 func (db *DB) AddFile(filename, contentType string, data []byte) (f *File, err error) {
 	f = &File{}
@@ -0,0 +1,333 @@
+package db
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/termora/berry/db/search"
+)
+
+// DiffOp is the kind of a part of a text diff
+type DiffOp string
+
+// Diff operations
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffPart is a run of text that was unchanged, added, or removed
+type DiffPart struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// FieldChange is a change to one of a term's fields.
+// Text fields have Diff set, list fields (aliases and tags) have Added and Removed set.
+type FieldChange struct {
+	Field   string     `json:"field"`
+	Diff    []DiffPart `json:"diff,omitempty"`
+	Added   []string   `json:"added,omitempty"`
+	Removed []string   `json:"removed,omitempty"`
+}
+
+// Before returns a text field's value before the change
+func (c FieldChange) Before() string {
+	var b strings.Builder
+	for _, p := range c.Diff {
+		if p.Op != DiffInsert {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+// After returns a text field's value after the change
+func (c FieldChange) After() string {
+	var b strings.Builder
+	for _, p := range c.Diff {
+		if p.Op != DiffDelete {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+var fieldNames = map[string]string{
+	"name":             "Name",
+	"aliases":          "Aliases",
+	"category":         "Category",
+	"content_warnings": "Content warnings",
+	"description":      "Description",
+	"source":           "Source",
+	"note":             "Note",
+	"tags":             "Tags",
+}
+
+// FieldName returns the display name of a term field in a FieldChange
+func FieldName(field string) string {
+	if n, ok := fieldNames[field]; ok {
+		return n
+	}
+	return field
+}
+
+// Editor is a user who chose to be credited in public term history
+type Editor struct {
+	UserID discord.UserID `json:"user_id"`
+	Name   string         `json:"name"`
+}
+
+// Revision is a public audit log entry for a term.
+// Private data is already redacted: Editor is nil unless the user chose to be credited, and private reasons are left out.
+type Revision struct {
+	ID        int64     `json:"id"`
+	TermID    int       `json:"term_id"`
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+	// Name is the term's name after the change, or before it was deleted
+	Name    string        `json:"name"`
+	Editor  *Editor       `json:"editor"`
+	Reason  string        `json:"reason,omitempty"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type historyRow struct {
+	ID            int64
+	SubjectID     int
+	Action        string
+	Timestamp     time.Time
+	Before        []byte
+	After         []byte
+	Reason        *string
+	ReasonPrivate bool
+	EditorID      *discord.UserID
+	EditorName    *string
+}
+
+const historyQuery = `select a.id, a.subject_id, a.action::text as action, a.timestamp, a.before, a.after, a.reason, a.reason_private,
+	c.user_id as editor_id, c.name as editor_name
+	from audit_log as a left join history_credits as c on c.user_id = a.user_id `
+
+// TermHistory returns the public revisions of a term, newest first.
+// Like RevisionsSince, terms hidden from search have no public history.
+func (db *DB) TermHistory(id int) (revs []Revision, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting history for term %v", id)
+
+	var rows []historyRow
+	err = pgxscan.Select(ctx, db.Pool, &rows, historyQuery+`join terms as t on t.id = a.subject_id
+	where a.subject = 'term' and a.subject_id = $1 and t.flags & $2 = 0
+	order by a.id desc`, id, search.FlagSearchHidden)
+	if err != nil {
+		return nil, err
+	}
+	return revisions(rows), nil
+}
+
+// RevisionsSince returns the public revisions of terms since the given time, newest first.
+// Only edits to terms that are still in the glossary and not hidden from search are returned.
+func (db *DB) RevisionsSince(t time.Time) (revs []Revision, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting term revisions since %s", t)
+
+	var rows []historyRow
+	err = pgxscan.Select(ctx, db.Pool, &rows, historyQuery+`join terms as t on t.id = a.subject_id
+	where a.subject = 'term' and a.action = 'update' and a.timestamp > $1 and t.flags & $2 = 0
+	order by a.id desc`, t, search.FlagSearchHidden)
+	if err != nil {
+		return nil, err
+	}
+	return revisions(rows), nil
+}
+
+func revisions(rows []historyRow) []Revision {
+	revs := make([]Revision, 0, len(rows))
+	for _, r := range rows {
+		rev := Revision{
+			ID:        r.ID,
+			TermID:    r.SubjectID,
+			Action:    r.Action,
+			Timestamp: r.Timestamp,
+			Changes:   []FieldChange{},
+		}
+		if r.EditorID != nil && r.EditorName != nil {
+			rev.Editor = &Editor{UserID: *r.EditorID, Name: *r.EditorName}
+		}
+		if r.Reason != nil && !r.ReasonPrivate {
+			rev.Reason = *r.Reason
+		}
+
+		var before, after Term
+		if r.Before != nil {
+			if err := json.Unmarshal(r.Before, &before); err != nil {
+				Debug("Error decoding audit log entry %v: %v", r.ID, err)
+			}
+		}
+		if r.After != nil {
+			if err := json.Unmarshal(r.After, &after); err != nil {
+				Debug("Error decoding audit log entry %v: %v", r.ID, err)
+			}
+		}
+
+		switch r.Action {
+		case "create":
+			rev.Name = after.Name
+			rev.Changes = TermChanges(&Term{}, &after)
+		case "update":
+			rev.Name = after.Name
+			rev.Changes = TermChanges(&before, &after)
+		default:
+			rev.Name = before.Name
+		}
+		revs = append(revs, rev)
+	}
+	return revs
+}
+
+// TermChanges returns the changes between two versions of a term, in the order the fields are shown on the term's page
+func TermChanges(before, after *Term) (changes []FieldChange) {
+	changes = []FieldChange{}
+
+	text := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, FieldChange{Field: field, Diff: TextDiff(a, b)})
+		}
+	}
+	list := func(field string, a, b []string) {
+		added, removed := ListDiff(a, b)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	text("name", before.Name, after.Name)
+	list("aliases", before.Aliases, after.Aliases)
+	text("category", before.CategoryName, after.CategoryName)
+	text("content_warnings", before.ContentWarnings, after.ContentWarnings)
+	text("description", before.Description, after.Description)
+	text("source", before.Source, after.Source)
+	text("note", before.Note, after.Note)
+	list("tags", before.DisplayTags, after.DisplayTags)
+	return changes
+}
+
+// ListDiff returns the items in b that aren't in a, and the items in a that aren't in b
+func ListDiff(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+
+	for _, s := range b {
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+var diffTokenRegexp = regexp.MustCompile(`\s+|\S+`)
+
+// TextDiff returns a word-level diff between a and b
+func TextDiff(a, b string) (parts []DiffPart) {
+	x := diffTokenRegexp.FindAllString(a, -1)
+	y := diffTokenRegexp.FindAllString(b, -1)
+
+	add := func(op DiffOp, s string) {
+		if n := len(parts); n > 0 && parts[n-1].Op == op {
+			parts[n-1].Text += s
+			return
+		}
+		parts = append(parts, DiffPart{Op: op, Text: s})
+	}
+
+	// the common prefix and suffix don't need to be in the table
+	var start int
+	for start < len(x) && start < len(y) && x[start] == y[start] {
+		add(DiffEqual, x[start])
+		start++
+	}
+	endX, endY := len(x), len(y)
+	for endX > start && endY > start && x[endX-1] == y[endY-1] {
+		endX--
+		endY--
+	}
+	mx, my := x[start:endX], y[start:endY]
+
+	// lcs[i*(len(my)+1)+j] is the length of the longest common subsequence of mx[i:] and my[j:]
+	w := len(my) + 1
+	lcs := make([]int32, (len(mx)+1)*w)
+	for i := len(mx) - 1; i >= 0; i-- {
+		for j := len(my) - 1; j >= 0; j-- {
+			if mx[i] == my[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else if lcs[(i+1)*w+j] >= lcs[i*w+j+1] {
+				lcs[i*w+j] = lcs[(i+1)*w+j]
+			} else {
+				lcs[i*w+j] = lcs[i*w+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(mx) && j < len(my) {
+		switch {
+		case mx[i] == my[j]:
+			add(DiffEqual, mx[i])
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			add(DiffDelete, mx[i])
+			i++
+		default:
+			add(DiffInsert, my[j])
+			j++
+		}
+	}
+	for ; i < len(mx); i++ {
+		add(DiffDelete, mx[i])
+	}
+	for ; j < len(my); j++ {
+		add(DiffInsert, my[j])
+	}
+
+	for k := endX; k < len(x); k++ {
+		add(DiffEqual, x[k])
+	}
+	return parts
+}
+
+// SetHistoryCredit sets whether a user is credited by name in public term history.
+// The name is updated every time it's set.
+func (db *DB) SetHistoryCredit(userID discord.UserID, name string, credit bool) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	if !credit {
+		_, err = db.Exec(ctx, "delete from history_credits where user_id = $1", userID)
+		return err
+	}
+
+	_, err = db.Exec(ctx, `insert into history_credits (user_id, name) values ($1, $2)
+	on conflict (user_id) do update set name = $2`, userID, name)
+	return err
+}
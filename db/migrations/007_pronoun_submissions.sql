@@ -0,0 +1,36 @@
+-- +migrate Up
+
+-- 2026-10-19: track pronoun submissions
+-- replaces the reaction-based pronoun_msgs table
+
+create type pronoun_submission_status as enum ('pending', 'approved', 'rejected', 'merged');
+
+create table pronoun_submissions (
+    id  serial  primary key,
+
+    -- 0 for submissions carried over from pronoun_msgs, as those didn't store the submitter
+    user_id     bigint  not null,
+    message_id  bigint  not null default 0,
+
+    subjective  text    not null,
+    objective   text    not null,
+    poss_det    text    not null,
+    poss_pro    text    not null,
+    reflexive   text    not null,
+
+    status          pronoun_submission_status   not null default 'pending',
+    reason          text,
+    merged_into     int     references pronouns (id) on delete set null,
+    moderator_id    bigint  not null default 0,
+
+    submitted   timestamp   not null default (current_timestamp at time zone 'utc'),
+    resolved    timestamp
+);
+
+create index pronoun_submissions_message_id_idx on pronoun_submissions (message_id);
+create index pronoun_submissions_pending_idx on pronoun_submissions (lower(subjective), lower(objective)) where status = 'pending';
+
+insert into pronoun_submissions (user_id, message_id, subjective, objective, poss_det, poss_pro, reflexive)
+    select 0, message_id, subjective, objective, poss_det, poss_pro, reflexive from pronoun_msgs;
+
+drop table pronoun_msgs;
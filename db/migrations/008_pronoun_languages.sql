@@ -0,0 +1,26 @@
+-- +migrate Up
+
+-- 2026-10-19: language-aware pronoun sets
+-- forms holds every form in the order of the language's slots,
+-- the named columns are only filled for English sets
+
+alter table pronouns add column language text not null default 'en';
+alter table pronouns add column forms text[] not null default '{}';
+
+update pronouns set forms = array[subjective, objective, poss_det, poss_pro, reflexive];
+
+alter table pronouns drop constraint if exists pronouns_subjective_objective_poss_det_poss_pro_reflexive_key;
+alter table pronouns add constraint pronouns_language_forms_key unique (language, forms);
+
+create index pronouns_language_forms_idx on pronouns (language, lower(forms[1]), lower(forms[2]));
+
+alter table pronoun_submissions add column language text not null default 'en';
+alter table pronoun_submissions add column forms text[] not null default '{}';
+
+update pronoun_submissions set forms = array[subjective, objective, poss_det, poss_pro, reflexive];
+
+alter table pronoun_submissions alter column subjective set default '';
+alter table pronoun_submissions alter column objective set default '';
+alter table pronoun_submissions alter column poss_det set default '';
+alter table pronoun_submissions alter column poss_pro set default '';
+alter table pronoun_submissions alter column reflexive set default '';
@@ -0,0 +1,22 @@
+-- +migrate Up
+
+-- 2026-10-19: user pronoun profiles
+-- sets either reference an existing pronoun set, or are custom sets with their own forms
+
+create table user_pronouns (
+    id          serial  primary key,
+    user_id     bigint  not null,
+
+    pronoun_id  int     references pronouns (id) on delete cascade,
+    language    text    not null default 'en',
+    forms       text[]  not null default '{}', -- only used for custom sets
+
+    name        text, -- optional name to use in examples
+    position    int     not null default 0,
+    created     timestamp   not null default (current_timestamp at time zone 'utc'),
+
+    check (pronoun_id is not null or cardinality(forms) > 0)
+);
+
+create index user_pronouns_user_id_idx on user_pronouns (user_id, position);
+create index user_pronouns_pronoun_id_idx on user_pronouns (pronoun_id);
@@ -0,0 +1,28 @@
+-- +migrate Up
+
+-- 2026-10-19: API keys and usage accounting
+-- only a hash of each key is stored, the key itself is shown once when it's created
+
+create table api_keys (
+    id          serial  primary key,
+    name        text    not null,
+    prefix      text    not null, -- first few characters of the key, to recognise it
+    key_hash    text    not null unique,
+
+    created_by  bigint  not null default 0, -- 0 if created from the command line
+    created     timestamp   not null default (current_timestamp at time zone 'utc'),
+    last_used   timestamp,
+    revoked     timestamp,
+
+    rate_limit  int -- requests per minute, null for the default
+);
+
+-- usage is counted per key (null for anonymous requests), endpoint, and day
+create table api_usage (
+    key_id      int     references api_keys (id) on delete cascade,
+    endpoint    text    not null,
+    day         date    not null default (current_timestamp at time zone 'utc')::date,
+    count       bigint  not null default 0
+);
+
+create unique index api_usage_key_endpoint_day_idx on api_usage (coalesce(key_id, 0), endpoint, day);
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+-- 2026-10-19: API key scopes, for the write API
+-- keys are read-only by default, director and admin keys can edit the glossary
+
+alter table api_keys add column scope text not null default 'read';
+alter table api_keys add constraint api_keys_scope_check check (scope in ('read', 'director', 'admin'));
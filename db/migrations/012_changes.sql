@@ -0,0 +1,58 @@
+-- +migrate Up
+
+-- 2026-10-19: change log for incremental mirroring
+-- filled by triggers, so changes made outside the bot (API, CLI, manual queries) are included too
+
+create table changes (
+    id  bigserial   primary key,
+
+    subject     audit_log_entry_subject not null,
+    subject_id  int                     not null,
+    action      audit_log_action        not null,
+
+    timestamp   timestamp   not null default (current_timestamp at time zone 'utc')
+);
+
+-- +migrate StatementBegin
+create function log_change() returns trigger as $$
+begin
+    if (tg_op = 'DELETE') then
+        insert into changes (subject, subject_id, action) values (tg_argv[0]::audit_log_entry_subject, old.id, 'delete');
+        return old;
+    elsif (tg_op = 'UPDATE') then
+        insert into changes (subject, subject_id, action) values (tg_argv[0]::audit_log_entry_subject, new.id, 'update');
+    else
+        insert into changes (subject, subject_id, action) values (tg_argv[0]::audit_log_entry_subject, new.id, 'create');
+    end if;
+    return new;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger terms_log_change after insert or delete on terms
+    for each row execute procedure log_change('term');
+create trigger terms_log_update after update on terms
+    for each row when (old.* is distinct from new.*) execute procedure log_change('term');
+
+create trigger explanations_log_change after insert or delete on explanations
+    for each row execute procedure log_change('explanation');
+create trigger explanations_log_update after update on explanations
+    for each row when ((old.name, old.aliases, old.description) is distinct from (new.name, new.aliases, new.description))
+    execute procedure log_change('explanation');
+
+-- pronoun sets are updated every time they're used, so only log changes to the forms
+create trigger pronouns_log_change after insert or delete on pronouns
+    for each row execute procedure log_change('pronouns');
+create trigger pronouns_log_update after update on pronouns
+    for each row when ((old.language, old.forms) is distinct from (new.language, new.forms))
+    execute procedure log_change('pronouns');
+
+-- existing entries are added as created, so mirrors can start from an empty cursor
+insert into changes (subject, subject_id, action, timestamp)
+select subject, subject_id, 'create', timestamp from (
+    select 'term'::audit_log_entry_subject as subject, id as subject_id, created as timestamp from terms
+    union all
+    select 'explanation'::audit_log_entry_subject, id, created from explanations
+    union all
+    select 'pronouns'::audit_log_entry_subject, id, (current_timestamp at time zone 'utc') from pronouns
+) as existing order by timestamp, subject, subject_id;
@@ -0,0 +1,35 @@
+-- +migrate Up
+
+-- 2026-10-19: outgoing webhooks
+-- events are "<subject>.<action>" (like "term.update"), "<subject>.*", or "*"
+
+create table webhooks (
+    id      serial  primary key,
+    url     text    not null,
+    secret  text    not null, -- used to sign payloads, shown once when the webhook is created
+    events  text[]  not null default array['*']::text[],
+
+    created_by  bigint      not null default 0,
+    created     timestamp   not null default (current_timestamp at time zone 'utc'),
+
+    -- consecutive failed deliveries, reset on every successful one
+    failures        int         not null default 0,
+    disabled        timestamp,
+    disabled_reason text
+);
+
+-- every delivery attempt, for debugging
+create table webhook_deliveries (
+    id          bigserial   primary key,
+    webhook_id  int         not null references webhooks (id) on delete cascade,
+    delivery_id uuid        not null, -- the same for all attempts of a single delivery
+    event       text        not null,
+    attempt     int         not null,
+
+    status      int, -- null if no response was received
+    error       text,
+
+    timestamp   timestamp   not null default (current_timestamp at time zone 'utc')
+);
+
+create index webhook_deliveries_webhook_idx on webhook_deliveries (webhook_id, id desc);
@@ -0,0 +1,6 @@
+-- +migrate Up
+
+-- 2026-10-19: indexes for getting the latest term change cheaply, used for caching and conditional requests
+
+create index changes_subject_id_idx on changes (subject, id);
+create index terms_last_modified_idx on terms (last_modified);
@@ -0,0 +1,43 @@
+-- +migrate Up
+
+-- 2026-10-19: notify listeners in every process when glossary data changes
+-- the payload is {"table": ..., "action": "insert"|"update"|"delete", "key": ...}, where key is the row's primary key as text
+
+-- +migrate StatementBegin
+create function notify_change() returns trigger as $$
+declare
+    r record;
+begin
+    if (tg_op = 'DELETE') then
+        r := old;
+    else
+        r := new;
+    end if;
+
+    perform pg_notify('berry_changes', json_build_object(
+        'table', tg_table_name,
+        'action', lower(tg_op),
+        'key', to_jsonb(r)->>tg_argv[0]
+    )::text);
+    return null;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger terms_notify after insert or delete on terms
+    for each row execute procedure notify_change('id');
+create trigger terms_notify_update after update on terms
+    for each row when (old.* is distinct from new.*) execute procedure notify_change('id');
+create trigger tags_notify after insert or update or delete on tags
+    for each row execute procedure notify_change('normalized');
+create trigger categories_notify after insert or update or delete on categories
+    for each row execute procedure notify_change('id');
+create trigger explanations_notify after insert or update or delete on explanations
+    for each row execute procedure notify_change('id');
+
+-- pronoun sets are updated every time they're used, so only notify for changes to the forms
+create trigger pronouns_notify after insert or delete on pronouns
+    for each row execute procedure notify_change('id');
+create trigger pronouns_notify_update after update on pronouns
+    for each row when ((old.language, old.forms) is distinct from (new.language, new.forms))
+    execute procedure notify_change('id');
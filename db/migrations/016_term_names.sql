@@ -0,0 +1,51 @@
+-- +migrate Up
+
+-- 2026-10-19: case- and diacritic-insensitive lookup of terms by name or alias
+-- term_names is filled by a trigger, so it's always in sync with terms
+
+create extension if not exists unaccent;
+
+-- unaccent() isn't immutable (it depends on the search path), so it can't be used in an index directly
+-- +migrate StatementBegin
+create function normalize_name(text) returns text as $$
+    select lower(public.unaccent('public.unaccent'::regdictionary, trim($1)))
+$$ language sql immutable strict parallel safe;
+-- +migrate StatementEnd
+
+create table term_names (
+    term_id     int     not null references terms (id) on delete cascade,
+    name        text    not null,
+    normalized  text    not null,
+    alias       boolean not null
+);
+
+create index term_names_normalized_idx on term_names (normalized);
+create index term_names_term_id_idx on term_names (term_id);
+
+-- +migrate StatementBegin
+create function update_term_names() returns trigger as $$
+begin
+    delete from term_names where term_id = new.id;
+
+    insert into term_names (term_id, name, normalized, alias)
+    values (new.id, new.name, normalize_name(new.name), false);
+
+    insert into term_names (term_id, name, normalized, alias)
+    select new.id, a, normalize_name(a), true from unnest(new.aliases) as a where trim(a) != '';
+
+    return null;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger terms_update_names after insert on terms
+    for each row execute procedure update_term_names();
+create trigger terms_update_names_update after update on terms
+    for each row when ((old.name, old.aliases) is distinct from (new.name, new.aliases))
+    execute procedure update_term_names();
+
+insert into term_names (term_id, name, normalized, alias)
+select id, name, normalize_name(name), false from terms;
+
+insert into term_names (term_id, name, normalized, alias)
+select t.id, a, normalize_name(a), true from terms as t, unnest(t.aliases) as a where trim(a) != '';
@@ -0,0 +1,87 @@
+-- +migrate Up
+
+-- 2026-10-19: stable slugs for website URLs
+-- every slug a term has had is kept in term_slugs, so old URLs keep working after it's renamed
+
+-- +migrate StatementBegin
+create function slugify(text) returns text as $$
+    select trim(both '-' from regexp_replace(normalize_name($1), '[^[:alnum:]]+', '-', 'g'))
+$$ language sql immutable strict parallel safe;
+-- +migrate StatementEnd
+
+alter table terms add column slug text;
+
+create table term_slugs (
+    slug    text    primary key,
+    term_id int     not null references terms (id) on delete cascade,
+    created timestamp not null default (current_timestamp at time zone 'utc')
+);
+
+create index term_slugs_term_id_idx on term_slugs (term_id);
+
+-- sets the term's slug before it's saved, the slug is added to term_slugs after
+-- +migrate StatementBegin
+create function set_term_slug() returns trigger as $$
+declare
+    s text;
+begin
+    s := slugify(new.name);
+    if (s = '') then
+        s := 'term-' || new.id;
+    elsif (s ~ '^\d+$') then
+        -- purely numeric slugs would be taken for IDs
+        s := 'term-' || s;
+    end if;
+
+    -- slugs (including old ones) can't be reused by another term
+    if exists (select from term_slugs where slug = s and term_id != new.id) then
+        s := s || '-' || new.id;
+    end if;
+
+    new.slug := s;
+    return new;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+create function add_term_slug() returns trigger as $$
+begin
+    insert into term_slugs (slug, term_id) values (new.slug, new.id) on conflict (slug) do nothing;
+    return null;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger terms_set_slug before insert on terms
+    for each row execute procedure set_term_slug();
+create trigger terms_set_slug_update before update on terms
+    for each row when (old.name is distinct from new.name or new.slug is null) execute procedure set_term_slug();
+
+create trigger terms_add_slug after insert on terms
+    for each row execute procedure add_term_slug();
+create trigger terms_add_slug_update after update on terms
+    for each row when (old.slug is distinct from new.slug) execute procedure add_term_slug();
+
+-- existing terms get slugs in ID order, so older terms get the unsuffixed slug if two have the same name.
+-- this isn't a change to the terms, so it isn't logged or sent to listeners.
+alter table terms disable trigger terms_log_update;
+alter table terms disable trigger terms_notify_update;
+
+-- +migrate StatementBegin
+do $$
+declare
+    t record;
+begin
+    for t in select id from terms order by id loop
+        update terms set slug = null where id = t.id;
+    end loop;
+end;
+$$;
+-- +migrate StatementEnd
+
+alter table terms enable trigger terms_log_update;
+alter table terms enable trigger terms_notify_update;
+
+alter table terms alter column slug set not null;
+create unique index terms_slug_idx on terms (slug);
@@ -0,0 +1,18 @@
+-- +migrate Up
+
+-- 2026-10-19: public term history
+-- audit log reasons can be marked private, and users can choose to be credited on public history pages
+
+-- existing reasons were already shown in the public audit log channel, so they stay public
+alter table audit_log add column reason_private boolean not null default false;
+
+create table history_credits (
+    user_id bigint  primary key,
+    name    text    not null -- updated whenever the user opts in again
+);
+
+create index audit_log_subject_idx on audit_log (subject, subject_id, id);
+
+-- feeds include term revisions, so they're regenerated when the audit log changes
+create trigger audit_log_notify after insert or update on audit_log
+    for each row execute procedure notify_change('id');
@@ -0,0 +1,28 @@
+-- +migrate Up
+
+-- 2026-10-19: web dashboard for directors
+-- logins are one-time links sent by the bot, which are exchanged for a session cookie.
+-- only hashes of both tokens are stored.
+
+create table dashboard_logins (
+    token_hash  text    primary key,
+    user_id     bigint  not null,
+    name        text    not null,
+    scope       text    not null, -- "director" or "admin", the same as API key scopes
+
+    expires     timestamp   not null
+);
+
+create table dashboard_sessions (
+    token_hash  text    primary key,
+    user_id     bigint  not null,
+    name        text    not null,
+    scope       text    not null,
+    -- sent with every form, so other sites can't make changes using the session cookie
+    csrf_token  text    not null,
+
+    created     timestamp   not null default (current_timestamp at time zone 'utc'),
+    expires     timestamp   not null
+);
+
+create index dashboard_sessions_user_idx on dashboard_sessions (user_id);
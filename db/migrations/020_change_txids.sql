@@ -0,0 +1,12 @@
+-- +migrate Up
+
+-- 2026-10-19: order the change log by transaction
+-- change IDs are assigned before a transaction commits, so a long transaction can commit a change with a lower ID than one that's already been read.
+-- instead, changes are ordered by the ID of the transaction that made them, and only returned once every transaction before theirs has finished.
+-- txid_current() is used over xid8 so this works on Postgres 12.
+
+-- existing changes are all committed, so they keep their order by ID
+alter table changes add column txid bigint not null default 0;
+alter table changes alter column txid set default txid_current();
+
+create index changes_txid_id_idx on changes (txid, id);
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- 2026-10-19: queue webhook deliveries in the database
+-- retries used to wait in memory, so they were lost whenever a process restarted.
+-- every attempt is still a row. pending attempts have next_attempt set, and are picked up by any process that sends webhooks.
+
+alter table webhook_deliveries add column next_attempt timestamp;
+-- the exact body that's sent, only kept until the attempt is made
+alter table webhook_deliveries add column payload text;
+
+create index webhook_deliveries_pending_idx on webhook_deliveries (next_attempt) where next_attempt is not null;
@@ -0,0 +1,100 @@
+-- +migrate Up
+
+-- 2026-10-19: stable tag slugs, and slug-only term updates aren't changes
+-- like term_slugs, every slug a tag has had is kept in tag_slugs, so old URLs keep working after it's renamed
+
+-- slugs are derived from other columns, so changing one isn't a change to the term itself
+drop trigger terms_log_update on terms;
+create trigger terms_log_update after update on terms
+    for each row when ((to_jsonb(old) - 'slug') is distinct from (to_jsonb(new) - 'slug'))
+    execute procedure log_change('term');
+
+drop trigger terms_notify_update on terms;
+create trigger terms_notify_update after update on terms
+    for each row when ((to_jsonb(old) - 'slug') is distinct from (to_jsonb(new) - 'slug'))
+    execute procedure notify_change('id');
+
+alter table tags add column slug text;
+
+-- tag is the normalized name of the tag the slug belongs to. tags are deleted and re-added when renamed,
+-- so this isn't a foreign key; renaming a tag moves its slugs to the new name instead.
+create table tag_slugs (
+    slug    text    primary key,
+    tag     text    not null,
+    created timestamp not null default (current_timestamp at time zone 'utc')
+);
+
+create index tag_slugs_tag_idx on tag_slugs (tag);
+
+-- sets the tag's slug before it's saved, the slug is added to tag_slugs after
+-- +migrate StatementBegin
+create function set_tag_slug() returns trigger as $$
+declare
+    base text;
+    s text;
+    n int := 1;
+begin
+    -- a tag that's been re-added keeps the slug it had
+    select slug into s from tag_slugs where tag = new.normalized order by created desc limit 1;
+    if found then
+        new.slug := s;
+        return new;
+    end if;
+
+    base := slugify(new.normalized);
+    if (base = '') then
+        base := 'tag';
+    end if;
+
+    -- slugs (including old ones) can't be reused by another tag
+    s := base;
+    while exists (select from tag_slugs where slug = s) loop
+        n := n + 1;
+        s := base || '-' || n;
+    end loop;
+
+    new.slug := s;
+    return new;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+create function add_tag_slug() returns trigger as $$
+begin
+    insert into tag_slugs (slug, tag) values (new.slug, new.normalized) on conflict (slug) do nothing;
+    return null;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger tags_set_slug before insert on tags
+    for each row execute procedure set_tag_slug();
+create trigger tags_set_slug_update before update on tags
+    for each row when (old.normalized is distinct from new.normalized or new.slug is null) execute procedure set_tag_slug();
+
+create trigger tags_add_slug after insert on tags
+    for each row execute procedure add_tag_slug();
+create trigger tags_add_slug_update after update on tags
+    for each row when (old.slug is distinct from new.slug) execute procedure add_tag_slug();
+
+-- existing tags get slugs in the order the website numbered them in, so their URLs don't change.
+-- this isn't a change to the tags, so listeners aren't notified of it.
+alter table tags disable trigger tags_notify;
+
+-- +migrate StatementBegin
+do $$
+declare
+    t record;
+begin
+    for t in select normalized from tags order by normalized loop
+        update tags set slug = null where normalized = t.normalized;
+    end loop;
+end;
+$$;
+-- +migrate StatementEnd
+
+alter table tags enable trigger tags_notify;
+
+alter table tags alter column slug set not null;
+create unique index tags_slug_idx on tags (slug);
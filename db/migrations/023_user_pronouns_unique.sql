@@ -0,0 +1,14 @@
+-- +migrate Up
+
+-- 2026-10-19: a set can only be saved to a user's profile once
+
+-- keep the first of any duplicates
+delete from user_pronouns as a using user_pronouns as b
+    where a.user_id = b.user_id and a.id > b.id
+    and (a.pronoun_id = b.pronoun_id
+        or (a.pronoun_id is null and b.pronoun_id is null and a.language = b.language and a.forms = b.forms));
+
+alter table user_pronouns add constraint user_pronouns_user_id_pronoun_id_key unique (user_id, pronoun_id);
+
+-- custom sets don't have a pronoun_id, so they're unique by their forms instead
+create unique index user_pronouns_custom_idx on user_pronouns (user_id, language, forms) where pronoun_id is null;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- 2026-10-19: notify the API when keys change, so revoked keys are removed from its cache right away
+-- last_used is updated on every flush of the usage counts, so changes to only that aren't sent
+
+create trigger api_keys_notify after insert or delete on api_keys
+    for each row execute procedure notify_change('id');
+create trigger api_keys_notify_update after update on api_keys
+    for each row when ((to_jsonb(old) - 'last_used') is distinct from (to_jsonb(new) - 'last_used'))
+    execute procedure notify_change('id');
@@ -13,7 +13,7 @@ func (db *DB) TermsSince(d time.Time) (t []*Term, err error) {
 
 	Debug("Getting terms added since %s", d)
 
-	err = pgxscan.Select(ctx, db.Pool, &t, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.flags, t.content_warnings
+	err = pgxscan.Select(ctx, db.Pool, &t, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.content_warnings
 	from public.terms as t, public.categories as c
 	where t.category = c.id and t.created > $1 and t.flags & 1 = 0
 	order by name asc`, d)
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db/search"
+)
+
+const notifyChannel = "berry_changes"
+
+// Tables that send notifications
+const (
+	TermsTable        = "terms"
+	TagsTable         = "tags"
+	CategoriesTable   = "categories"
+	ExplanationsTable = "explanations"
+	PronounsTable     = "pronouns"
+	AuditLogTable     = "audit_log"
+	APIKeysTable      = "api_keys"
+)
+
+// Notification actions. ReconnectAction is sent by the listener itself after reconnecting,
+// as anything could have changed while it was disconnected.
+const (
+	InsertAction    = "insert"
+	UpdateAction    = "update"
+	DeleteAction    = "delete"
+	ReconnectAction = "reconnect"
+)
+
+// Notification is a change to a row in one of the tables that send notifications
+type Notification struct {
+	Table  string `json:"table"`
+	Action string `json:"action"`
+	// Key is the row's primary key, the ID for everything except tags
+	Key string `json:"key"`
+}
+
+// ID returns the notification's key as an integer, or 0 if it isn't one
+func (n Notification) ID() int {
+	id, _ := strconv.Atoi(n.Key)
+	return id
+}
+
+// listener calls handlers for every notification sent on notifyChannel
+type listener struct {
+	mu       sync.RWMutex
+	handlers []func(Notification)
+
+	once sync.Once
+	// 1 while connected
+	connected int32
+}
+
+// OnChange adds a function that's called whenever glossary data changes.
+// Handlers are called in order in the listener's goroutine, so they shouldn't block for long.
+// Listen must be called for handlers to be called.
+func (db *DB) OnChange(fn func(Notification)) {
+	db.listener.mu.Lock()
+	db.listener.handlers = append(db.listener.handlers, fn)
+	db.listener.mu.Unlock()
+}
+
+// Listen starts listening for changes in the background, until ctx is cancelled.
+// While it's connected, the term cache only checks for changes when it's notified of one.
+// Calling Listen more than once does nothing.
+func (db *DB) Listen(ctx context.Context) {
+	db.listener.once.Do(func() {
+		go db.listen(ctx)
+	})
+}
+
+func (db *DB) listen(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := db.listenConn(ctx)
+		atomic.StoreInt32(&db.listener.connected, 0)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorf("Error listening for database changes, reconnecting in %v: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// listenConn listens on a dedicated connection, so it doesn't take one from the pool
+func (db *DB) listenConn(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, db.Pool.Config().ConnConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "listen "+notifyChannel)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&db.listener.connected, 1)
+	log.Info("Listening for database changes")
+
+	// changes made while we weren't listening were missed
+	db.notify(Notification{Action: ReconnectAction})
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ev Notification
+		err = json.Unmarshal([]byte(n.Payload), &ev)
+		if err != nil {
+			log.Errorf("Error decoding notification %q: %v", n.Payload, err)
+			continue
+		}
+
+		Debug("Received notification: %v %v %v", ev.Table, ev.Action, ev.Key)
+		db.notify(ev)
+	}
+}
+
+func (db *DB) notify(n Notification) {
+	// terms include their category's name and tags' display names, so all three invalidate them
+	switch n.Table {
+	case TermsTable, TagsTable, CategoriesTable, "":
+		db.InvalidateTerms()
+	}
+
+	db.listener.mu.RLock()
+	handlers := db.listener.handlers
+	db.listener.mu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(n)
+	}
+}
+
+// listening returns true if the listener is connected
+func (db *DB) listening() bool {
+	return atomic.LoadInt32(&db.listener.connected) == 1
+}
+
+// SyncSearch keeps the search index up to date with term changes made in any process.
+// It can be passed to OnChange; syncing does nothing with the default postgres backend, so it's only needed with Typesense.
+// Only one process should use it (the bot does), as writes don't sync terms themselves.
+// Every time the listener connects, including the first time, all terms are synced, as changes could have been missed.
+func (db *DB) SyncSearch(n Notification) {
+	var terms []*Term
+	switch {
+	case n.Action == ReconnectAction:
+		if err := db.SyncAllTerms(); err != nil {
+			log.Errorf("Error synchronizing terms with search index: %v", err)
+			return
+		}
+		log.Info("Synchronized terms with search instance!")
+		return
+	case n.Table == TermsTable && n.Action == DeleteAction:
+		if err := db.SyncDelete(n.ID()); err != nil {
+			log.Errorf("Error removing term %v from search index: %v", n.Key, err)
+		}
+		return
+	case n.Table == TermsTable:
+		t, err := db.GetTerm(n.ID())
+		if err != nil {
+			log.Errorf("Error getting term %v to sync: %v", n.Key, err)
+			return
+		}
+		terms = []*Term{t}
+	case n.Table == CategoriesTable && n.Action == UpdateAction:
+		// terms are indexed with their category's name
+		var err error
+		terms, err = db.GetCategoryTerms(n.ID(), 0)
+		if err != nil {
+			log.Errorf("Error getting terms in category %v to sync: %v", n.Key, err)
+			return
+		}
+	default:
+		return
+	}
+
+	for _, t := range terms {
+		var err error
+		if t.SearchHidden() {
+			err = db.SyncDelete(t.ID)
+		} else {
+			err = db.SyncTerm(t)
+		}
+		if err != nil {
+			log.Errorf("Error syncing term %v with search index: %v", t.ID, err)
+		}
+	}
+}
+
+// SyncAllTerms replaces the search index with all terms that aren't hidden from search
+func (db *DB) SyncAllTerms() error {
+	terms, err := db.GetTerms(search.FlagSearchHidden)
+	if err != nil {
+		return err
+	}
+	return db.SyncTerms(terms)
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// DefaultLanguage is the language pronoun sets are in if none is given
+const DefaultLanguage = "en"
+
+// ErrUnknownLanguage is returned when a pronoun language isn't known
+var ErrUnknownLanguage = errors.New("unknown pronoun language")
+
+// PronounLanguage is the pronoun schema for a single language
+type PronounLanguage struct {
+	Code  string        `json:"code"`
+	Name  string        `json:"name"`
+	Slots []PronounSlot `json:"slots"`
+}
+
+// PronounSlot is a single form in a language's pronoun sets
+type PronounSlot struct {
+	// Key is the name the form is available as in example templates
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Example string `json:"example"`
+}
+
+// PronounLanguages are all languages pronoun sets can be in.
+// Every language needs a directory of example templates in commands/pronouns/examples.
+var PronounLanguages = []PronounLanguage{
+	{
+		Code: "en",
+		Name: "English",
+		Slots: []PronounSlot{
+			{Key: "Subjective", Name: "Subjective form", Example: "she"},
+			{Key: "Objective", Name: "Objective form", Example: "her"},
+			{Key: "PossDet", Name: "Possessive determiner", Example: "*her* pen"},
+			{Key: "PossPro", Name: "Possessive pronoun", Example: "that pen is *hers*"},
+			{Key: "Reflexive", Name: "Reflexive form", Example: "herself"},
+		},
+	},
+	{
+		Code: "de",
+		Name: "German",
+		Slots: []PronounSlot{
+			{Key: "Nom", Name: "Nominativ", Example: "xier"},
+			{Key: "Akk", Name: "Akkusativ", Example: "ich sehe *xien*"},
+			{Key: "Dat", Name: "Dativ", Example: "ich helfe *xiem*"},
+			{Key: "Gen", Name: "Genitiv", Example: "statt *xieser*"},
+			{Key: "Poss", Name: "Possessivartikel", Example: "*xies* Buch"},
+		},
+	},
+	{
+		Code: "sv",
+		Name: "Swedish",
+		Slots: []PronounSlot{
+			{Key: "Subj", Name: "Subjekt", Example: "hen"},
+			{Key: "Obj", Name: "Objekt", Example: "jag ser *henom*"},
+			{Key: "Poss", Name: "Possessiv", Example: "*hens* bok"},
+		},
+	},
+	{
+		Code: "es",
+		Name: "Spanish",
+		Slots: []PronounSlot{
+			{Key: "Pron", Name: "Pronombre", Example: "elle"},
+			{Key: "Obj", Name: "Pronombre de objeto", Example: "*le* vi"},
+			{Key: "Art", Name: "Artículo definido", Example: "*le* niñe"},
+			{Key: "Indef", Name: "Artículo indefinido", Example: "*une* amigue"},
+			{Key: "End", Name: "Terminación", Example: "amig*e*"},
+		},
+	},
+}
+
+// PronounLanguageByCode returns the language with the given code.
+// An empty code returns the default language.
+func PronounLanguageByCode(code string) (l PronounLanguage, ok bool) {
+	if code == "" {
+		code = DefaultLanguage
+	}
+
+	for _, l := range PronounLanguages {
+		if strings.EqualFold(l.Code, code) {
+			return l, true
+		}
+	}
+	return l, false
+}
+
+// PronounLanguageChoices returns all languages as slash command choices
+func PronounLanguageChoices() (c []discord.StringChoice) {
+	for _, l := range PronounLanguages {
+		c = append(c, discord.StringChoice{Name: l.Name, Value: l.Code})
+	}
+	return c
+}
+
+// NewPronounSet creates a pronoun set in the given language, checking the number of forms.
+func NewPronounSet(lang string, forms []string) (p PronounSet, err error) {
+	l, ok := PronounLanguageByCode(lang)
+	if !ok {
+		return p, ErrUnknownLanguage
+	}
+
+	if len(forms) < len(l.Slots) {
+		return p, ErrNoForms
+	}
+	if len(forms) > len(l.Slots) {
+		return p, ErrTooManyForms
+	}
+
+	p.Language = l.Code
+	p.Forms = make([]string, len(forms))
+	for i := range forms {
+		p.Forms[i] = strings.TrimSpace(forms[i])
+		if p.Forms[i] == "" {
+			return p, ErrNoForms
+		}
+	}
+
+	if l.Code == "en" {
+		p.Subjective, p.Objective, p.PossDet, p.PossPro, p.Reflexive = p.Forms[0], p.Forms[1], p.Forms[2], p.Forms[3], p.Forms[4]
+	}
+	return p, nil
+}
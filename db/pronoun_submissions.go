@@ -0,0 +1,339 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+)
+
+// SubmissionStatus is the status of a pronoun submission
+type SubmissionStatus string
+
+// Submission statuses
+const (
+	SubmissionPending  SubmissionStatus = "pending"
+	SubmissionApproved SubmissionStatus = "approved"
+	SubmissionRejected SubmissionStatus = "rejected"
+	SubmissionMerged   SubmissionStatus = "merged"
+)
+
+// ErrSubmissionResolved is returned when trying to resolve a submission that's no longer pending
+var ErrSubmissionResolved = errors.New("submission was already resolved")
+
+// ErrPronounExists is returned when approving a submission for a set that's already in the database
+type ErrPronounExists struct {
+	// ID is the existing set's ID
+	ID int
+}
+
+func (e *ErrPronounExists) Error() string {
+	return fmt.Sprintf("pronoun set already exists with ID %v", e.ID)
+}
+
+// PronounSubmission is a single pronoun submission
+type PronounSubmission struct {
+	ID int
+
+	UserID    discord.UserID
+	MessageID discord.MessageID
+
+	Language string
+	Forms    []string
+
+	Subjective string
+	Objective  string
+	PossDet    string
+	PossPro    string
+	Reflexive  string
+
+	Status      SubmissionStatus
+	Reason      *string
+	MergedInto  *int
+	ModeratorID discord.UserID
+
+	Submitted time.Time
+	Resolved  *time.Time
+}
+
+// Set returns the submission's forms as a PronounSet
+func (s PronounSubmission) Set() PronounSet {
+	return PronounSet{
+		Language:   s.Language,
+		Forms:      s.Forms,
+		Subjective: s.Subjective,
+		Objective:  s.Objective,
+		PossDet:    s.PossDet,
+		PossPro:    s.PossPro,
+		Reflexive:  s.Reflexive,
+	}
+}
+
+// ResolvedMessage returns the message sent to the submitter when the submission is resolved.
+// mergedInto is the set the submission was merged into, if it's known.
+func (s PronounSubmission) ResolvedMessage(mergedInto *PronounSet) (msg string) {
+	switch s.Status {
+	case SubmissionApproved:
+		msg = fmt.Sprintf("Your pronoun submission **%v** has been approved! You can now use it with the `pronouns` command.", s.Set())
+	case SubmissionRejected:
+		msg = fmt.Sprintf("Your pronoun submission **%v** has been rejected.", s.Set())
+		if s.Reason != nil {
+			msg += "\nReason: " + *s.Reason
+		}
+	case SubmissionMerged:
+		msg = fmt.Sprintf("Your pronoun submission **%v** was a duplicate of an existing set.", s.Set())
+		if mergedInto != nil {
+			msg = fmt.Sprintf("Your pronoun submission **%v** was a duplicate of **%v**, which is already in the bot.", s.Set(), mergedInto)
+		}
+	}
+	return msg
+}
+
+// AddPronounSubmission adds a pending pronoun submission
+func (db *DB) AddPronounSubmission(userID discord.UserID, p PronounSet) (s PronounSubmission, err error) {
+	p, err = NewPronounSet(p.Lang(), p.FormList())
+	if err != nil {
+		return s, err
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Adding %v pronoun submission %s by %v", p.Language, p, userID)
+
+	subj, obj, possDet, possPro, refl := p.englishForms()
+	err = pgxscan.Get(ctx, db.Pool, &s, `insert into pronoun_submissions
+	(user_id, language, forms, subjective, objective, poss_det, poss_pro, reflexive)
+	values ($1, $2, $3, $4, $5, $6, $7, $8) returning *`, userID, p.Language, p.Forms, subj, obj, possDet, possPro, refl)
+	return s, err
+}
+
+// SetPronounSubmissionMessage sets the message ID of a submission's staff message
+func (db *DB) SetPronounSubmissionMessage(id int, msgID discord.MessageID) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, "update pronoun_submissions set message_id = $1 where id = $2", msgID, id)
+	return
+}
+
+// PronounSubmission gets a submission by ID
+func (db *DB) PronounSubmission(id int) (s PronounSubmission, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Get(ctx, db.Pool, &s, "select * from pronoun_submissions where id = $1", id)
+	return
+}
+
+// PendingPronounSubmissions returns all submissions awaiting review, oldest first
+func (db *DB) PendingPronounSubmissions() (subs []PronounSubmission, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db.Pool, &subs, "select * from pronoun_submissions where status = 'pending' order by id")
+	return
+}
+
+// LegacyPronounSubmissions returns pending submissions carried over from the old reaction-based system that still have a staff message
+func (db *DB) LegacyPronounSubmissions() (subs []PronounSubmission, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db.Pool, &subs, "select * from pronoun_submissions where status = 'pending' and user_id = 0 and message_id <> 0 order by id")
+	return
+}
+
+// PronounSubmissionPending returns true if an identical set is already awaiting review
+func (db *DB) PronounSubmissionPending(p PronounSet) (exists bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	forms := p.FormList()
+	for i := range forms {
+		forms[i] = strings.ToLower(forms[i])
+	}
+
+	err = db.QueryRow(ctx, `select exists(select * from pronoun_submissions where
+	language = $1 and lower(forms::text)::text[] = $2 and status = 'pending')`, p.Lang(), forms).Scan(&exists)
+	return
+}
+
+// ApprovePronounSubmission adds a submission's pronoun set to the database and marks it as approved
+func (db *DB) ApprovePronounSubmission(id int, modID discord.UserID) (s PronounSubmission, set *PronounSet, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Approving pronoun submission %v", id)
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return s, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = pgxscan.Get(ctx, tx, &s, "select * from pronoun_submissions where id = $1 for update", id)
+	if err != nil {
+		return s, nil, err
+	}
+	if s.Status != SubmissionPending {
+		return s, nil, ErrSubmissionResolved
+	}
+
+	// the set might have been added since it was submitted, either directly or by approving another submission
+	var existing int
+	err = tx.QueryRow(ctx, "select id from pronouns where language = $1 and forms = $2", s.Language, s.Forms).Scan(&existing)
+	if err == nil {
+		return s, nil, &ErrPronounExists{ID: existing}
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return s, nil, err
+	}
+
+	set = &PronounSet{}
+	err = pgxscan.Get(ctx, tx, set, `insert into pronouns
+	(language, forms, subjective, objective, poss_det, poss_pro, reflexive)
+	values ($1, $2, $3, $4, $5, $6, $7) returning `+pronounColumns+`, uses`,
+		s.Language, s.Forms, s.Subjective, s.Objective, s.PossDet, s.PossPro, s.Reflexive)
+	if err != nil {
+		return s, nil, err
+	}
+
+	err = pgxscan.Get(ctx, tx, &s, `update pronoun_submissions set
+	status = 'approved', moderator_id = $1, resolved = (current_timestamp at time zone 'utc')
+	where id = $2 returning *`, modID, id)
+	if err != nil {
+		return s, nil, err
+	}
+
+	return s, set, tx.Commit(ctx)
+}
+
+// RejectPronounSubmission marks a submission as rejected
+func (db *DB) RejectPronounSubmission(id int, modID discord.UserID, reason string) (s PronounSubmission, err error) {
+	Debug("Rejecting pronoun submission %v", id)
+
+	return db.resolvePronounSubmission(id, SubmissionRejected, modID, &reason, nil)
+}
+
+// MergePronounSubmission marks a submission as a duplicate of an existing set
+func (db *DB) MergePronounSubmission(id int, modID discord.UserID, into int) (s PronounSubmission, err error) {
+	Debug("Merging pronoun submission %v into %v", id, into)
+
+	return db.resolvePronounSubmission(id, SubmissionMerged, modID, nil, &into)
+}
+
+func (db *DB) resolvePronounSubmission(id int, status SubmissionStatus, modID discord.UserID, reason *string, into *int) (s PronounSubmission, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Get(ctx, db.Pool, &s, `update pronoun_submissions set
+	status = $1, moderator_id = $2, reason = $3, merged_into = $4, resolved = (current_timestamp at time zone 'utc')
+	where id = $5 and status = 'pending' returning *`, status, modID, reason, into, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// either the submission doesn't exist or it was already resolved
+			s, err = db.PronounSubmission(id)
+			if err == nil {
+				err = ErrSubmissionResolved
+			}
+		}
+		return s, err
+	}
+	return s, nil
+}
+
+// maxSimilarDistance is the maximum total edit distance between two sets for them to be considered near-duplicates
+const maxSimilarDistance = 3
+
+// SimilarPronouns checks existing pronoun sets in the same language for duplicates of p.
+// exact is non-nil if a set with the same forms (ignoring case) exists,
+// similar contains up to five near-duplicates, closest first.
+func (db *DB) SimilarPronouns(p PronounSet) (exact *PronounSet, similar []*PronounSet, err error) {
+	sets, err := db.LanguagePronouns(p.Lang(), AlphabeticPronounOrder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type match struct {
+		set  *PronounSet
+		dist int
+	}
+	var matches []match
+
+	for _, set := range sets {
+		a, b := p.FormList(), set.FormList()
+		if len(a) != len(b) {
+			continue
+		}
+
+		dist := pronounDistance(a, b)
+		if dist == 0 {
+			return set, nil, nil
+		}
+
+		// sets with the same first two forms are likely variants of each other
+		if dist <= maxSimilarDistance ||
+			(len(a) >= 2 && strings.EqualFold(a[0], b[0]) && strings.EqualFold(a[1], b[1])) {
+			matches = append(matches, match{set, dist})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+
+	for i, m := range matches {
+		if i >= 5 {
+			break
+		}
+		similar = append(similar, m.set)
+	}
+	return nil, similar, nil
+}
+
+func pronounDistance(a, b []string) (dist int) {
+	for i := range a {
+		dist += levenshtein(strings.ToLower(a[i]), strings.ToLower(b[i]))
+	}
+	return dist
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(is ...int) int {
+	m := is[0]
+	for _, i := range is[1:] {
+		if i < m {
+			m = i
+		}
+	}
+	return m
+}
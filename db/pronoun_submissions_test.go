@@ -0,0 +1,50 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// TestApproveExistingPronouns checks that approving a submission for a set that's already in the database returns ErrPronounExists
+func TestApproveExistingPronouns(t *testing.T) {
+	db := testDB(t)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	set, err := NewPronounSet(DefaultLanguage, []string{"xa" + suffix, "xb" + suffix, "xc" + suffix, "xd" + suffix, "xe" + suffix})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subs []PronounSubmission
+	for i := 0; i < 2; i++ {
+		s, err := db.AddPronounSubmission(discord.UserID(1), set)
+		if err != nil {
+			t.Fatalf("adding submission: %v", err)
+		}
+		subs = append(subs, s)
+	}
+
+	_, approved, err := db.ApprovePronounSubmission(subs[0].ID, discord.UserID(1))
+	if err != nil {
+		t.Fatalf("approving submission: %v", err)
+	}
+	t.Cleanup(func() { db.RemovePronoun(approved.ID) })
+
+	_, _, err = db.ApprovePronounSubmission(subs[1].ID, discord.UserID(1))
+	var exists *ErrPronounExists
+	if !errors.As(err, &exists) || exists.ID != approved.ID {
+		t.Fatalf("expected ErrPronounExists with ID %v, got %v", approved.ID, err)
+	}
+
+	s, err := db.PronounSubmission(subs[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != SubmissionPending {
+		t.Fatalf("expected the submission to still be pending, got %v", s.Status)
+	}
+}
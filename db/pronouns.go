@@ -3,7 +3,9 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
@@ -13,7 +15,11 @@ import (
 
 // PronounSet is a single set of pronouns
 type PronounSet struct {
-	ID         int    `json:"id"`
+	ID       int      `json:"id"`
+	Language string   `json:"language"`
+	Forms    []string `json:"forms"`
+
+	// The English forms, empty for sets in other languages
 	Subjective string `json:"subjective"`
 	Objective  string `json:"objective"`
 	PossDet    string `json:"possessive_determiner"`
@@ -25,7 +31,50 @@ type PronounSet struct {
 }
 
 func (p PronounSet) String() string {
-	return p.Subjective + "/" + p.Objective + "/" + p.PossDet + "/" + p.PossPro + "/" + p.Reflexive
+	return strings.Join(p.FormList(), "/")
+}
+
+// Lang returns the set's language code
+func (p PronounSet) Lang() string {
+	if p.Language == "" {
+		return DefaultLanguage
+	}
+	return p.Language
+}
+
+// FormList returns all the set's forms in order.
+// English sets can have only the named fields filled, so those are used for English if they're set.
+func (p PronounSet) FormList() []string {
+	if p.Lang() == "en" && (p.Subjective != "" || len(p.Forms) == 0) {
+		return []string{p.Subjective, p.Objective, p.PossDet, p.PossPro, p.Reflexive}
+	}
+	return p.Forms
+}
+
+// FormMap returns the set's forms keyed by their language's slot keys
+func (p PronounSet) FormMap() map[string]string {
+	m := map[string]string{}
+
+	l, ok := PronounLanguageByCode(p.Lang())
+	if !ok {
+		return m
+	}
+
+	forms := p.FormList()
+	for i, s := range l.Slots {
+		if i < len(forms) {
+			m[s.Key] = forms[i]
+		}
+	}
+	return m
+}
+
+// englishForms returns the forms stored in the English columns, which are empty for other languages
+func (p PronounSet) englishForms() (s, o, pd, pp, r string) {
+	if p.Lang() != "en" {
+		return
+	}
+	return strings.TrimSpace(p.Subjective), strings.TrimSpace(p.Objective), strings.TrimSpace(p.PossDet), strings.TrimSpace(p.PossPro), strings.TrimSpace(p.Reflexive)
 }
 
 // Errors ...
@@ -35,51 +84,130 @@ var (
 	ErrNoForms        = errors.New("no forms given")
 )
 
-// GetPronoun gets a pronoun from the database
-// gods this function is shit but idc, if it works it works
+const pronounColumns = "id, language, forms, subjective, objective, poss_det, poss_pro, reflexive"
+
+// GetPronoun gets an English pronoun set from the database
 func (db *DB) GetPronoun(forms ...string) (sets []*PronounSet, err error) {
+	return db.LanguagePronoun(DefaultLanguage, forms...)
+}
+
+// LanguagePronoun gets pronoun sets in the given language, matching the given forms in order
+func (db *DB) LanguagePronoun(lang string, forms ...string) (sets []*PronounSet, err error) {
+	l, ok := PronounLanguageByCode(lang)
+	if !ok {
+		return nil, ErrUnknownLanguage
+	}
+
+	if len(forms) == 0 {
+		return nil, ErrNoForms
+	}
+	if len(forms) > len(l.Slots) {
+		return nil, ErrTooManyForms
+	}
+
 	ctx, cancel := db.Context()
 	defer cancel()
 
-	Debug("Getting pronouns %v", strings.Join(forms, "/"))
+	Debug("Getting %v pronouns %v", l.Code, strings.Join(forms, "/"))
 
-	switch len(forms) {
-	case 0:
-		return nil, ErrNoForms
-	case 1:
-		err = pgxscan.Select(ctx, db.Pool, &sets, "select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns where lower(subjective) = lower($1) order by sorting, subjective, objective, poss_det, poss_pro, reflexive", forms[0])
-		if err != nil {
-			return
+	sql := "select " + pronounColumns + " from pronouns where language = $1"
+	args := []interface{}{l.Code}
+	for i, f := range forms {
+		args = append(args, strings.TrimSpace(f))
+		sql += fmt.Sprintf(" and lower(forms[%d]) = lower($%d)", i+1, len(args))
+	}
+	sql += " order by sorting, forms"
+
+	err = pgxscan.Select(ctx, db.Pool, &sets, sql, args...)
+	if err != nil {
+		return
+	}
+	if len(sets) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return sets, nil
+}
+
+// ParsePronouns resolves user input to one or more pronoun sets.
+// If mixed is false and more than one set is returned, the input matched more than one set.
+func (db *DB) ParsePronouns(lang, input string) (sets []*PronounSet, mixed bool, err error) {
+	if strings.Contains(input, "+") {
+		sets, err = db.MixedPronouns(lang, input)
+		return sets, true, err
+	}
+
+	forms := strings.Split(input, "/")
+	sets, err = db.LanguagePronoun(lang, forms...)
+	// this might be a list of subjective forms, like she/they
+	if errors.Is(err, pgx.ErrNoRows) && len(forms) > 1 {
+		if m, mixedErr := db.MixedPronouns(lang, input); mixedErr == nil {
+			return m, true, nil
 		}
-	case 2:
-		err = pgxscan.Select(ctx, db.Pool, &sets, "select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns where lower(subjective) = lower($1) and lower(objective) = lower($2) order by sorting, subjective, objective, poss_det, poss_pro, reflexive", forms[0], forms[1])
-		if err != nil {
-			return
+	}
+	return sets, false, err
+}
+
+// MixedPronouns resolves input containing more than one pronoun set,
+// either separated with a plus (he/him+xe/xem) or as a list of subjective forms (she/they).
+// Each set resolves to the first match from LanguagePronoun.
+func (db *DB) MixedPronouns(lang, input string) (sets []*PronounSet, err error) {
+	var parts [][]string
+	if strings.Contains(input, "+") {
+		for _, s := range strings.Split(input, "+") {
+			parts = append(parts, strings.Split(s, "/"))
 		}
-	case 3:
-		err = pgxscan.Select(ctx, db.Pool, &sets, "select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns where lower(subjective) = lower($1) and lower(objective) = lower($2) and lower(poss_det) = lower($3) order by sorting, subjective, objective, poss_det, poss_pro, reflexive", forms[0], forms[1], forms[2])
-		if err != nil {
-			return
+	} else {
+		for _, s := range strings.Split(input, "/") {
+			parts = append(parts, []string{s})
 		}
-	case 4:
-		err = pgxscan.Select(ctx, db.Pool, &sets, "select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns where lower(subjective) = lower($1) and lower(objective) = lower($2) and lower(poss_det) = lower($3) and lower(poss_pro) = lower($4) order by sorting, subjective, objective, poss_det, poss_pro, reflexive", forms[0], forms[1], forms[2], forms[3])
-		if err != nil {
-			return
+	}
+
+	for _, forms := range parts {
+		for i := range forms {
+			forms[i] = strings.TrimSpace(forms[i])
 		}
-	case 5:
-		err = pgxscan.Select(ctx, db.Pool, &sets, "select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns where lower(subjective) = lower($1) and lower(objective) = lower($2) and lower(poss_det) = lower($3) and lower(poss_pro) = lower($4) and lower(reflexive) = lower($5) order by sorting, subjective, objective, poss_det, poss_pro, reflexive", forms[0], forms[1], forms[2], forms[3], forms[4])
+
+		matches, err := db.LanguagePronoun(lang, forms...)
 		if err != nil {
-			return
+			return nil, err
 		}
-	default:
-		return nil, ErrTooManyForms
-	}
-	if len(sets) == 0 {
-		return nil, pgx.ErrNoRows
+		sets = append(sets, matches[0])
 	}
 	return sets, nil
 }
 
+// PronounSetByID gets a pronoun set by its ID
+func (db *DB) PronounSetByID(id int) (p *PronounSet, err error) {
+	p = &PronounSet{}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting pronoun set %v", id)
+
+	err = pgxscan.Get(ctx, db.Pool, p, "select "+pronounColumns+", uses from pronouns where id = $1", id)
+	return p, err
+}
+
+// FindPronounSet finds a single English pronoun set by ID or (partial) forms, separated with slashes.
+// ErrMoreThanOneRow is returned if the forms match more than one set.
+func (db *DB) FindPronounSet(s string) (*PronounSet, error) {
+	s = strings.TrimSpace(s)
+
+	if id, err := strconv.Atoi(s); err == nil {
+		return db.PronounSetByID(id)
+	}
+
+	sets, err := db.GetPronoun(strings.Split(s, "/")...)
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) != 1 {
+		return nil, ErrMoreThanOneRow
+	}
+	return sets[0], nil
+}
+
 // RandomPronouns gets a random pronoun set from the database
 func (db *DB) RandomPronouns() (p *PronounSet, err error) {
 	var pronouns []*PronounSet
@@ -89,7 +217,7 @@ func (db *DB) RandomPronouns() (p *PronounSet, err error) {
 
 	Debug("Getting random pronouns")
 
-	err = pgxscan.Select(ctx, db.Pool, &pronouns, `select id, subjective, objective, poss_det, poss_pro, reflexive from pronouns order by id`)
+	err = pgxscan.Select(ctx, db.Pool, &pronouns, "select "+pronounColumns+" from pronouns order by id")
 	if err != nil {
 		return
 	}
@@ -104,21 +232,63 @@ func (db *DB) RandomPronouns() (p *PronounSet, err error) {
 
 // AddPronoun adds a pronoun set, returning the ID
 func (db *DB) AddPronoun(p PronounSet) (id int, err error) {
-	if p.Subjective == "" || p.Objective == "" || p.PossDet == "" || p.PossPro == "" || p.Reflexive == "" {
-		return 0, ErrNoForms
+	p, err = NewPronounSet(p.Lang(), p.FormList())
+	if err != nil {
+		return 0, err
 	}
 
-	Debug("Adding pronouns %s", p)
+	Debug("Adding %v pronouns %s", p.Language, p)
 
 	ctx, cancel := db.Context()
 	defer cancel()
 
-	err = db.QueryRow(ctx, "insert into pronouns (subjective, objective, poss_det, poss_pro, reflexive) values ($1, $2, $3, $4, $5) returning id",
-		strings.TrimSpace(p.Subjective), strings.TrimSpace(p.Objective), strings.TrimSpace(p.PossDet), strings.TrimSpace(p.PossPro), strings.TrimSpace(p.Reflexive),
-	).Scan(&id)
+	subj, obj, possDet, possPro, refl := p.englishForms()
+	err = db.QueryRow(ctx, `insert into pronouns (language, forms, subjective, objective, poss_det, poss_pro, reflexive)
+	values ($1, $2, $3, $4, $5, $6, $7) returning id`, p.Language, p.Forms, subj, obj, possDet, possPro, refl).Scan(&id)
 	return id, err
 }
 
+// UpdatePronoun replaces the forms of a pronoun set
+func (db *DB) UpdatePronoun(id int, p PronounSet) (err error) {
+	p, err = NewPronounSet(p.Lang(), p.FormList())
+	if err != nil {
+		return err
+	}
+
+	Debug("Updating pronouns %v to %s", id, p)
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	subj, obj, possDet, possPro, refl := p.englishForms()
+	ct, err := db.Exec(ctx, `update pronouns set language = $1, forms = $2, subjective = $3, objective = $4, poss_det = $5, poss_pro = $6, reflexive = $7
+	where id = $8`, p.Language, p.Forms, subj, obj, possDet, possPro, refl, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return nil
+}
+
+// RemovePronoun removes a pronoun set
+func (db *DB) RemovePronoun(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Removing pronouns %v", id)
+
+	ct, err := db.Exec(ctx, "delete from pronouns where id = $1", id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return nil
+}
+
 type PronounOrder int
 
 const (
@@ -127,24 +297,27 @@ const (
 	RandomPronounOrder
 )
 
-// Pronouns ...
+// Pronouns returns pronoun sets in all languages
 func (db *DB) Pronouns(order PronounOrder) (p []*PronounSet, err error) {
+	return db.LanguagePronouns("", order)
+}
+
+// LanguagePronouns returns all pronoun sets in the given language, or all languages if lang is empty
+func (db *DB) LanguagePronouns(lang string, order PronounOrder) (p []*PronounSet, err error) {
 	ctx, cancel := db.Context()
 	defer cancel()
 
 	Debug("Getting all pronouns")
 
-	var sql string
+	sql := "select * from pronouns where ($1 = '' or language = $1)"
 	switch order {
 	case AlphabeticPronounOrder:
-		sql = "select * from pronouns order by sorting, subjective, objective, poss_det, poss_pro, reflexive"
+		sql += " order by language <> 'en', language, sorting, forms"
 	case UsesPronounOrder:
-		sql = "select * from pronouns order by uses desc, subjective asc, objective asc, poss_det asc, poss_pro asc, reflexive asc"
-	default:
-		sql = "select * from pronouns"
+		sql += " order by uses desc, language <> 'en', language, forms"
 	}
 
-	err = pgxscan.Select(ctx, db.Pool, &p, sql)
+	err = pgxscan.Select(ctx, db.Pool, &p, sql, lang)
 	return
 }
 
@@ -44,7 +44,7 @@ func (db *pg) Search(input string, limit int, ignore []string) (terms []*search.
 	defer cancel()
 
 	err = pgxscan.Select(ctx, db.Pool, &terms, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.flags, t.tags, t.content_warnings, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags,
 	ts_rank_cd(t.searchtext, websearch_to_tsquery('english', $1), 8) as rank,
 	ts_headline(t.description, websearch_to_tsquery('english', $1), 'StartSel=**, StopSel=**') as headline
@@ -78,7 +78,7 @@ func (db *pg) SearchCat(input string, cat, limit int, ignore []string) (terms []
 	defer cancel()
 
 	err = pgxscan.Select(ctx, db.Pool, &terms, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.flags, t.tags, t.content_warnings, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags,
 	ts_rank_cd(t.searchtext, websearch_to_tsquery('english', $1), 8) as rank,
 	ts_headline(t.description, websearch_to_tsquery('english', $1), 'StartSel=**, StopSel=**') as headline
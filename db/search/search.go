@@ -37,6 +37,7 @@ type Term struct {
 	Category        int       `json:"category_id"`
 	CategoryName    string    `json:"category"`
 	Name            string    `json:"name"`
+	Slug            string    `json:"slug,omitempty"`
 	Aliases         []string  `json:"aliases"`
 	Description     string    `json:"description"`
 	Note            string    `json:"note,omitempty"`
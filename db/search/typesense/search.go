@@ -105,7 +105,7 @@ func (c *Client) getTerm(ctx context.Context, conn *pgxpool.Conn, id int) (t *se
 	t = &search.Term{}
 
 	err = pgxscan.Get(ctx, conn, t, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.tags, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c where t.id = $1 and t.category = c.id`, id)
 	return t, err
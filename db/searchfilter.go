@@ -0,0 +1,157 @@
+package db
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SearchSort is the order filtered search results are returned in
+type SearchSort string
+
+// Sort orders for FilteredSearch
+const (
+	SortRelevance SearchSort = "relevance"
+	SortName      SearchSort = "name"
+	SortNewest    SearchSort = "newest"
+	SortUpdated   SearchSort = "updated"
+)
+
+// SearchSorts are all valid sort orders, in the order they're shown to users
+var SearchSorts = []SearchSort{SortRelevance, SortName, SortNewest, SortUpdated}
+
+// Errors returned by ParseSearchFilter
+var (
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrUnknownSort     = errors.New("unknown sort order")
+)
+
+// SearchFilter filters and sorts search results on the website and gemini
+type SearchFilter struct {
+	// Category is the category to search in, or 0 for all categories
+	Category int
+	// Ignore is a list of tags, terms with any of them are left out
+	Ignore []string
+	// NoCW leaves out terms with content warnings
+	NoCW bool
+	Sort SearchSort
+}
+
+// filteredSearchLimit is the number of results FilteredSearch returns
+const filteredSearchLimit = 50
+
+// ParseSearchFilter parses a filter from the category, exclude, no_cw, and sort query parameters.
+// The category can be given as an ID or a name, and excluded tags can be repeated or comma-separated.
+func (db *DB) ParseSearchFilter(v url.Values) (f SearchFilter, err error) {
+	f.Sort = SortRelevance
+
+	if cat := strings.TrimSpace(v.Get("category")); cat != "" {
+		f.Category, err = strconv.Atoi(cat)
+		if err != nil {
+			f.Category, err = db.CategoryID(cat)
+			if err != nil {
+				return f, ErrUnknownCategory
+			}
+		}
+	}
+
+	for _, s := range v["exclude"] {
+		for _, tag := range strings.Split(s, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				f.Ignore = append(f.Ignore, tag)
+			}
+		}
+	}
+
+	f.NoCW, _ = strconv.ParseBool(v.Get("no_cw"))
+
+	if s := v.Get("sort"); s != "" {
+		f.Sort = ""
+		for _, o := range SearchSorts {
+			if string(o) == s {
+				f.Sort = o
+			}
+		}
+		if f.Sort == "" {
+			return f, ErrUnknownSort
+		}
+	}
+	return f, nil
+}
+
+// Values returns the filter as query parameters, only including options that aren't the default
+func (f SearchFilter) Values() url.Values {
+	v := url.Values{}
+	if f.Category != 0 {
+		v.Set("category", strconv.Itoa(f.Category))
+	}
+	if len(f.Ignore) > 0 {
+		v.Set("exclude", strings.Join(f.Ignore, ","))
+	}
+	if f.NoCW {
+		v.Set("no_cw", "true")
+	}
+	if f.Sort != "" && f.Sort != SortRelevance {
+		v.Set("sort", string(f.Sort))
+	}
+	return v
+}
+
+// Ignores returns true if the filter excludes the given tag
+func (f SearchFilter) Ignores(tag string) bool {
+	for _, t := range f.Ignore {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilteredSearch searches for terms, then filters and sorts them.
+// Sorting only applies to the most relevant results, the same ones that are returned when sorting by relevance.
+func (db *DB) FilteredSearch(query string, f SearchFilter) (terms []*Term, err error) {
+	ignore := f.Ignore
+	if ignore == nil {
+		ignore = []string{}
+	}
+
+	// CW filtering happens after the search, so fetch more terms to still have enough left
+	fetch := filteredSearchLimit
+	if f.NoCW {
+		fetch *= 4
+	}
+
+	if f.Category != 0 {
+		terms, err = db.SearchCat(query, f.Category, fetch, ignore)
+	} else {
+		terms, err = db.Search(query, fetch, ignore)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.NoCW {
+		filtered := terms[:0]
+		for _, t := range terms {
+			if t.ContentWarnings == "" {
+				filtered = append(filtered, t)
+			}
+		}
+		terms = filtered
+	}
+	if len(terms) > filteredSearchLimit {
+		terms = terms[:filteredSearchLimit]
+	}
+
+	switch f.Sort {
+	case SortName:
+		sort.SliceStable(terms, func(i, j int) bool { return strings.ToLower(terms[i].Name) < strings.ToLower(terms[j].Name) })
+	case SortNewest:
+		sort.SliceStable(terms, func(i, j int) bool { return terms[i].Created.After(terms[j].Created) })
+	case SortUpdated:
+		sort.SliceStable(terms, func(i, j int) bool { return terms[i].LastModified.After(terms[j].LastModified) })
+	}
+	return terms, nil
+}
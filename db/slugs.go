@@ -0,0 +1,84 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+)
+
+// TermBySlug gets a term by one of its current or past slugs, using the term cache.
+// canonical is false if the slug is an old one, and the term should be linked to by t.Slug instead.
+// Returns pgx.ErrNoRows if no term ever had the slug.
+func (db *DB) TermBySlug(slug string) (t *Term, canonical bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting term with slug %v", slug)
+
+	var id int
+	err = db.QueryRow(ctx, "select term_id from term_slugs where slug = $1", slug).Scan(&id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	t, err = db.CachedTerm(id)
+	if err != nil {
+		return nil, false, err
+	}
+	return t, t.Slug == slug, nil
+}
+
+// Tag is a tag with its slug, for the website
+type Tag struct {
+	Name       string
+	Normalized string
+	Slug       string
+	// LastModified is the latest last_modified of the tag's terms
+	LastModified time.Time
+}
+
+// tagColumns are the columns selected into a Tag, from tags as tags
+const tagColumns = `tags.display as name, tags.normalized, tags.slug,
+	coalesce((select max(last_modified) from terms where tags.normalized = any(terms.tags)), 'epoch') as last_modified`
+
+// TagSlugs gets all tags with their slugs
+func (db *DB) TagSlugs() (tags []Tag, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting all tags with slugs")
+
+	err = pgxscan.Select(ctx, db.Pool, &tags, "select "+tagColumns+" from tags order by normalized")
+	return
+}
+
+// TagBySlug gets a tag by one of its current or past slugs.
+// canonical is false if the slug is an old one, and the tag should be linked to by t.Slug instead.
+// Returns pgx.ErrNoRows if no tag has ever had the slug.
+func (db *DB) TagBySlug(slug string) (t Tag, canonical bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting tag with slug %v", slug)
+
+	err = pgxscan.Get(ctx, db.Pool, &t, "select "+tagColumns+` from tag_slugs as s, tags
+	where s.slug = $1 and s.tag = tags.normalized`, slug)
+	if err != nil {
+		return t, false, err
+	}
+	return t, t.Slug == slug, nil
+}
+
+// TagByName gets a tag by its name, case-insensitively.
+// Returns pgx.ErrNoRows if there's no tag with that name.
+func (db *DB) TagByName(name string) (t Tag, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting tag with name %v", name)
+
+	err = pgxscan.Get(ctx, db.Pool, &t, "select "+tagColumns+" from tags where normalized = $1",
+		strings.ToLower(strings.TrimSpace(name)))
+	return
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTagSlugs checks that colliding tags get stable slugs, and that renamed tags keep their old slugs
+func TestTagSlugs(t *testing.T) {
+	db := testDB(t)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	first, second, renamed := "slug test "+suffix, "slug-test-"+suffix, "renamed slug test "+suffix
+	t.Cleanup(func() {
+		db.RemoveTag(second)
+		db.RemoveTag(renamed)
+	})
+
+	for _, name := range []string{first, second} {
+		if err := db.AddTag(name); err != nil {
+			t.Fatalf("adding tag %q: %v", name, err)
+		}
+	}
+
+	a, err := db.TagByName(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := db.TagByName(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Slug != "slug-test-"+suffix || b.Slug != "slug-test-"+suffix+"-2" {
+		t.Fatalf("expected the second tag to get a numbered slug, got %q and %q", a.Slug, b.Slug)
+	}
+
+	if err := db.RenameTag(first, renamed); err != nil {
+		t.Fatalf("renaming tag: %v", err)
+	}
+
+	tag, canonical, err := db.TagBySlug(a.Slug)
+	if err != nil {
+		t.Fatalf("getting tag by old slug: %v", err)
+	}
+	if canonical || tag.Normalized != renamed {
+		t.Fatalf("expected old slug to point to %q, got %q (canonical: %v)", renamed, tag.Normalized, canonical)
+	}
+
+	// the second tag's slug doesn't change just because the first one's name did
+	b2, err := db.TagByName(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b2.Slug != b.Slug {
+		t.Fatalf("second tag's slug changed from %q to %q", b.Slug, b2.Slug)
+	}
+}
+
+func TestRenameTagConflict(t *testing.T) {
+	db := testDB(t)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	first, second := "rename test "+suffix, "other rename test "+suffix
+	t.Cleanup(func() {
+		db.RemoveTag(first)
+		db.RemoveTag(second)
+	})
+
+	for _, name := range []string{first, second} {
+		if err := db.AddTag(name); err != nil {
+			t.Fatalf("adding tag %q: %v", name, err)
+		}
+	}
+
+	if err := db.RenameTag(first, strings.ToUpper(second)); err != ErrTagExists {
+		t.Fatalf("expected ErrTagExists, got %v", err)
+	}
+	if _, err := db.TagByName(first); err != nil {
+		t.Fatalf("tag was changed by a failed rename: %v", err)
+	}
+
+	// changing only the display name isn't a conflict
+	if err := db.RenameTag(first, strings.ToUpper(first)); err != nil {
+		t.Fatalf("renaming tag: %v", err)
+	}
+	tag, err := db.TagByName(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Name != strings.ToUpper(first) {
+		t.Fatalf("expected display name %q, got %q", strings.ToUpper(first), tag.Name)
+	}
+}
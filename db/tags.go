@@ -1,6 +1,9 @@
 package db
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/georgysavva/scany/pgxscan"
 )
 
@@ -23,7 +26,7 @@ func (db *DB) TagTerms(tag string) (t []*Term, err error) {
 	Debug("Getting terms with tag %v", tag)
 
 	err = pgxscan.Select(ctx, db.Pool, &t, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.image_url from public.terms as t, public.categories as c
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.image_url from public.terms as t, public.categories as c
 	where $1 ilike any(t.tags) and t.category = c.id order by t.name, t.id`, tag)
 	return
 }
@@ -36,7 +39,128 @@ func (db *DB) UntaggedTerms() (t []*Term, err error) {
 	Debug("Getting untagged terms")
 
 	err = pgxscan.Select(ctx, db.Pool, &t, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.image_url from public.terms as t, public.categories as c
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.image_url from public.terms as t, public.categories as c
 	where t.tags = array[]::text[] and t.category = c.id order by t.name, t.id`)
 	return
 }
+
+// AddTag adds a tag, or updates its display name if it already exists
+func (db *DB) AddTag(display string) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	display = strings.TrimSpace(display)
+
+	Debug("Adding tag %v", display)
+
+	_, err = db.Exec(ctx, `insert into public.tags (normalized, display) values ($1, $2)
+	on conflict (normalized) do update set display = $2`, strings.ToLower(display), display)
+	return
+}
+
+// ErrTagExists is returned by RenameTag if there's already a tag with the new name
+var ErrTagExists = errors.New("a tag with that name already exists")
+
+// RenameTag renames a tag, updating all terms with that tag.
+// Returns ErrTagExists if the new name is a different tag's name.
+func (db *DB) RenameTag(tag, display string) (err error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	display = strings.TrimSpace(display)
+	normalized := strings.ToLower(display)
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Renaming tag %v to %v", tag, display)
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if normalized != tag {
+		var exists bool
+		err = tx.QueryRow(ctx, "select exists(select from public.tags where normalized = $1)", normalized).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrTagExists
+		}
+	}
+
+	// if the normalized name changed, the tag gets a new slug here
+	ct, err := tx.Exec(ctx, "update public.tags set normalized = $1, display = $2 where normalized = $3", normalized, display, tag)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+
+	// the tag's old slugs now redirect to the new one
+	_, err = tx.Exec(ctx, "update public.tag_slugs set tag = $1 where tag = $2", normalized, tag)
+	if err != nil {
+		return err
+	}
+
+	// terms include their tags' display names, so they're modified even if only the display name changed
+	_, err = tx.Exec(ctx, `update public.terms set tags = array_replace(tags, $1, $2), last_modified = (current_timestamp at time zone 'utc')
+	where $1 = any(tags)`, tag, normalized)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	db.InvalidateTerms()
+	return nil
+}
+
+// RemoveTag removes a tag, removing it from all terms
+func (db *DB) RemoveTag(tag string) (err error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Removing tag %v", tag)
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ct, err := tx.Exec(ctx, "delete from public.tags where normalized = $1", tag)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+
+	// so the slugs can be used by a new tag
+	_, err = tx.Exec(ctx, "delete from public.tag_slugs where tag = $1", tag)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `update public.terms set tags = array_remove(tags, $1), last_modified = (current_timestamp at time zone 'utc')
+	where $1 = any(tags)`, tag)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	db.InvalidateTerms()
+	return nil
+}
@@ -15,6 +15,9 @@ import (
 // DisputedText ...
 const DisputedText = "This term is **disputed**. This means for one reason or another, the definiton or term is contested. This definition, like any other on this bot, is not definitive nor does it endorse any particular discourse stance and should not be taken as such."
 
+// WarningText is shown on terms with the warning flag set
+const WarningText = "This term is only in this glossary for the sake of completeness. It may be derogatory, exclusionary, or harmful, especially when applied to other people and not as a self-description. Use this term with extreme caution."
+
 // Term is an alias to search.Term
 type Term = search.Term
 
@@ -91,7 +94,7 @@ func (db *DB) TermEmbed(t *Term) discord.Embed {
 	if t.Warning() {
 		e.Fields = append(e.Fields, discord.EmbedField{
 			Name:  "Warning",
-			Value: "This term is only in this glossary for the sake of completeness. It may be derogatory, exclusionary, or harmful, especially when applied to other people and not as a self-description. Use this term with extreme caution.",
+			Value: WarningText,
 		})
 	}
 
@@ -191,6 +194,69 @@ func (db *DB) LinkTerms(input string) string {
 	return r.Replace(input)
 }
 
+// TermLink is a [[link]] to another term
+type TermLink struct {
+	// Text is the link as it appears in the input, including the brackets
+	Text string `json:"text"`
+	// Display is the text the link should be shown as
+	Display string `json:"display"`
+
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// URL is empty if there's no base URL set
+	URL string `json:"url,omitempty"`
+}
+
+// TermLinks returns all [[links]] in the input that link to an existing term
+func (db *DB) TermLinks(input string) (links []TermLink, err error) {
+	matches := linkRegexp.FindAllStringSubmatch(input, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	conn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	for _, i := range matches {
+		target, display := i[1], ""
+		if i[2] != "" {
+			target = strings.TrimPrefix(i[2], "|")
+			display = i[1]
+		}
+
+		id, name, err := db.findTerm(ctx, conn, target)
+		if err != nil {
+			continue
+		}
+
+		if display == "" {
+			display = name
+			// same as LinkTerms, lowercase links stay lowercase
+			if lowercaseRegexp.Match([]byte{i[1][0]}) {
+				display = strings.ToLower(name)
+			}
+		}
+
+		l := TermLink{
+			Text:    i[0],
+			Display: display,
+			ID:      id,
+			Name:    name,
+		}
+		if db.TermBaseURL != "" {
+			l.URL = db.TermBaseURL + strconv.Itoa(id)
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
 var numberRegex = regexp.MustCompile(`^\d+$`)
 
 func (db *DB) findTerm(ctx context.Context, conn *pgxpool.Conn, in string) (id int, name string, err error) {
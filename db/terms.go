@@ -37,7 +37,7 @@ func (db *DB) GetTerms(mask search.TermFlag) (terms []*Term, err error) {
 	Debug("Getting terms matching flags %v", mask)
 
 	err = pgxscan.Select(ctx, db.Pool, &terms, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.flags, t.tags, t.content_warnings, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c
 	where t.flags & $1 = 0 and t.category = c.id
@@ -53,7 +53,7 @@ func (db *DB) GetCategoryTerms(id int, mask search.TermFlag) (terms []*Term, err
 	Debug("Getting terms in category %v matching flags %v", id, mask)
 
 	err = pgxscan.Select(ctx, db.Pool, &terms, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.flags, t.tags, t.content_warnings, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c
 	where t.flags & $1 = 0 and t.category = $2
@@ -62,6 +62,46 @@ func (db *DB) GetCategoryTerms(id int, mask search.TermFlag) (terms []*Term, err
 	return terms, err
 }
 
+// TermFilter filters terms for FilterTerms
+type TermFilter struct {
+	// Category is the category ID, 0 for all categories
+	Category int
+	// Tags are normalized tag names, terms must have all of them
+	Tags []string
+	// Mask excludes terms with any of these flags
+	Mask search.TermFlag
+
+	Limit  int
+	Offset int
+}
+
+// FilterTerms gets a page of terms matching the filter, and the total number of matching terms
+func (db *DB) FilterTerms(f TermFilter) (terms []*Term, total int, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting terms matching %+v", f)
+
+	if f.Tags == nil {
+		f.Tags = []string{}
+	}
+
+	err = db.QueryRow(ctx, `select count(*) from public.terms
+	where flags & $1 = 0 and ($2 = 0 or category = $2) and tags @> $3`, f.Mask, f.Category, f.Tags).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = pgxscan.Select(ctx, db.Pool, &terms, `select
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.flags, t.tags, t.content_warnings, t.image_url,
+	array(select display from public.tags where normalized = any(t.tags)) as display_tags
+	from public.terms as t, public.categories as c
+	where t.flags & $1 = 0 and ($2 = 0 or t.category = $2) and t.tags @> $3
+	and t.category = c.id
+	order by t.name, t.id limit $4 offset $5`, f.Mask, f.Category, f.Tags, f.Limit, f.Offset)
+	return terms, total, err
+}
+
 // TermName gets a term by name
 func (db *DB) TermName(n string) (t []*Term, err error) {
 	ctx, cancel := db.Context()
@@ -70,12 +110,55 @@ func (db *DB) TermName(n string) (t []*Term, err error) {
 	Debug("Getting term with name %v", n)
 
 	err = pgxscan.Select(ctx, db.Pool, &t, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.tags, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c where (t.name ilike $1 or $2 ilike any(t.aliases)) and t.category = c.id`, n, n)
 	return t, err
 }
 
+// NameMatch is a term found by TermByName
+type NameMatch struct {
+	*Term
+	// Alias is the alias that matched, or empty if the term's name matched
+	Alias string
+	// Ambiguous is true if another term matched just as well
+	Ambiguous bool
+}
+
+// TermByName gets a term by its name or one of its aliases, ignoring case and diacritics.
+// Names are preferred over aliases, so if one term is named what another has as an alias, the first one is returned.
+// Returns pgx.ErrNoRows if no term matches.
+func (db *DB) TermByName(name string) (m NameMatch, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting term with name or alias %v", name)
+
+	var matches []struct {
+		TermID int
+		Name   string
+		Alias  bool
+	}
+	err = pgxscan.Select(ctx, db.Pool, &matches, `select distinct on (alias, term_id) term_id, name, alias
+	from term_names where normalized = normalize_name($1) order by alias, term_id`, name)
+	if err != nil {
+		return m, err
+	}
+	if len(matches) == 0 {
+		return m, pgx.ErrNoRows
+	}
+
+	m.Term, err = db.GetTerm(matches[0].TermID)
+	if err != nil {
+		return m, err
+	}
+	if matches[0].Alias {
+		m.Alias = matches[0].Name
+	}
+	m.Ambiguous = len(matches) > 1 && matches[1].Alias == matches[0].Alias
+	return m, nil
+}
+
 // AddTerm adds a term to the database
 func (db *DB) AddTerm(t *Term) (*Term, error) {
 	if t.Aliases == nil {
@@ -90,12 +173,16 @@ func (db *DB) AddTerm(t *Term) (*Term, error) {
 	ctx, cancel := db.Context()
 	defer cancel()
 
-	err := db.QueryRow(ctx, "insert into public.terms (name, category, aliases, description, source, aliases_string, tags) values ($1, $2, $3, $4, $5, $6, $7) returning id, created", t.Name, t.Category, t.Aliases, t.Description, t.Source, strings.Join(t.Aliases, ", "), t.Tags).Scan(&t.ID, &t.Created)
+	err := db.QueryRow(ctx, `insert into public.terms
+	(name, category, aliases, description, source, aliases_string, tags, note, content_warnings, flags, image_url)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) returning id, created, last_modified`,
+		t.Name, t.Category, t.Aliases, t.Description, t.Source, strings.Join(t.Aliases, ", "), t.Tags,
+		t.Note, t.ContentWarnings, t.Flags, t.ImageURL).Scan(&t.ID, &t.Created, &t.LastModified)
 	if err != nil {
 		return nil, err
 	}
 
-	return t, db.SyncTerm(t)
+	return t, nil
 }
 
 // RemoveTerm removes a term from the database
@@ -113,7 +200,7 @@ func (db *DB) RemoveTerm(id int) (err error) {
 		return ErrorNoRowsAffected
 	}
 
-	return db.SyncDelete(id)
+	return
 }
 
 // GetTerm gets a term by ID
@@ -126,7 +213,7 @@ func (db *DB) GetTerm(id int) (t *Term, err error) {
 	Debug("Getting term %v", id)
 
 	err = pgxscan.Get(ctx, db.Pool, t, `select
-	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.tags, t.image_url,
+	t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags, t.image_url,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c where t.id = $1 and t.category = c.id`, id)
 	return t, err
@@ -141,7 +228,7 @@ func (db *DB) RandomTerm(ignore []string) (t *Term, err error) {
 
 	Debug("Getting random term ignoring `%v`", ignore)
 
-	err = pgxscan.Select(ctx, db.Pool, &terms, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.tags,
+	err = pgxscan.Select(ctx, db.Pool, &terms, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c
 	where t.flags & $1 = 0 and t.category = c.id
@@ -172,7 +259,7 @@ func (db *DB) RandomTermCategory(id int, ignore []string) (t *Term, err error) {
 
 	Debug("Getting random term in %v ignoring `%v`", id, ignore)
 
-	err = pgxscan.Select(ctx, db.Pool, &terms, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.content_warnings, t.flags, t.tags,
+	err = pgxscan.Select(ctx, db.Pool, &terms, `select t.id, t.category, c.name as category_name, t.name, t.aliases, t.description, t.note, t.source, t.created, t.last_modified, t.slug, t.content_warnings, t.flags, t.tags,
 	array(select display from public.tags where normalized = any(t.tags)) as display_tags
 	from public.terms as t, public.categories as c
 	where t.flags & $1 = 0 and t.category = c.id
@@ -238,13 +325,15 @@ func (db *DB) UpdateDesc(id int, desc string) (err error) {
 
 	Debug("Updating description for %v to `%v`", id, desc)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set description = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", desc, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set description = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", desc, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // UpdateSource updates the source for a term
@@ -254,13 +343,15 @@ func (db *DB) UpdateSource(id int, source string) (err error) {
 
 	Debug("Updating source for %v to `%v`", id, source)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set source = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", source, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set source = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", source, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // UpdateTitle updates the title for a term
@@ -270,13 +361,15 @@ func (db *DB) UpdateTitle(id int, title string) (err error) {
 
 	Debug("Updating title for %v to `%v`", id, title)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set name = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", title, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set name = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", title, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // UpdateImage updates the image for a term
@@ -286,13 +379,15 @@ func (db *DB) UpdateImage(id int, img string) (err error) {
 
 	Debug("Updating image for %v to `%v`", id, img)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set image_url = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", img, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set image_url = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", img, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // UpdateAliases updates the aliases for a term
@@ -306,13 +401,15 @@ func (db *DB) UpdateAliases(id int, aliases []string) (err error) {
 		aliases = []string{}
 	}
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set aliases = $1, aliases_string = $2, last_modified = (current_timestamp at time zone 'utc') where id = $3 returning id, name, category, aliases, description, source, tags", aliases, strings.Join(aliases, ", "), id)
+	commandTag, err := db.Exec(ctx, "update public.terms set aliases = $1, aliases_string = $2, last_modified = (current_timestamp at time zone 'utc') where id = $3", aliases, strings.Join(aliases, ", "), id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // SetNote updates the note for a term
@@ -322,13 +419,15 @@ func (db *DB) SetNote(id int, note string) (err error) {
 
 	Debug("Updating note for %v to `%v`", id, note)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set note = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", note, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set note = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", note, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
 
-	return db.SyncTerm(&t)
+	return
 }
 
 // UpdateTags updates the tags for a term
@@ -338,11 +437,36 @@ func (db *DB) UpdateTags(id int, tags []string) (err error) {
 
 	Debug("Updating tags for %v to `%v`", id, tags)
 
-	var t Term
-	err = pgxscan.Get(ctx, db.Pool, &t, "update public.terms set tags = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2 returning id, name, category, aliases, description, source, tags", tags, id)
+	commandTag, err := db.Exec(ctx, "update public.terms set tags = $1, last_modified = (current_timestamp at time zone 'utc') where id = $2", tags, id)
 	if err != nil {
 		return
 	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+
+	return
+}
 
-	return db.SyncTerm(&t)
+// UpdateTerm updates all editable fields of a term at once
+func (db *DB) UpdateTerm(t *Term) (err error) {
+	if t.Aliases == nil {
+		t.Aliases = []string{}
+	}
+	if t.Tags == nil {
+		t.Tags = []string{}
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Updating term %v", t.ID)
+
+	err = db.QueryRow(ctx, `update public.terms set
+	name = $1, category = $2, aliases = $3, aliases_string = $4, description = $5, source = $6, tags = $7,
+	note = $8, content_warnings = $9, flags = $10, image_url = $11, last_modified = (current_timestamp at time zone 'utc')
+	where id = $12 returning last_modified`,
+		t.Name, t.Category, t.Aliases, strings.Join(t.Aliases, ", "), t.Description, t.Source, t.Tags,
+		t.Note, t.ContentWarnings, t.Flags, t.ImageURL, t.ID).Scan(&t.LastModified)
+	return
 }
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"strings"
 	"time"
 
 	"github.com/georgysavva/scany/pgxscan"
@@ -41,6 +42,42 @@ func (db *DB) GetExplanation(s string) (e *Explanation, err error) {
 	return e, err
 }
 
+// ExplanationByName gets an explanation by its name or one of its aliases, ignoring case.
+// Names take precedence over aliases.
+func (db *DB) ExplanationByName(name string) (e *Explanation, err error) {
+	e = &Explanation{}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting explanation with name or alias %v", name)
+
+	err = pgxscan.Get(ctx, db.Pool, e, `select id, name, aliases, description, created, as_command from public.explanations
+	where lower(name) = lower($1) or lower($1) = any(array(select lower(a) from unnest(aliases) a))
+	order by lower(name) = lower($1) desc, id limit 1`, name)
+	return e, err
+}
+
+// SearchExplanations returns explanations whose name, aliases, or description contain the query, ignoring case.
+// Explanations matching by name or alias come first.
+func (db *DB) SearchExplanations(query string) (e []*Explanation, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Searching explanations for %v", query)
+
+	err = pgxscan.Select(ctx, db.Pool, &e, `select id, name, aliases, description, created, as_command from public.explanations
+	where name ilike $1 or array_to_string(aliases, ' ') ilike $1 or description ilike $1
+	order by (name ilike $1 or array_to_string(aliases, ' ') ilike $1) desc, name`, pattern)
+	return e, err
+}
+
 // GetAllExplanations ...
 func (db *DB) GetAllExplanations() (e []*Explanation, err error) {
 	ctx, cancel := db.Context()
@@ -79,3 +116,78 @@ func (db *DB) SetAsCommand(id int, b bool) (err error) {
 	}
 	return
 }
+
+// ExplanationByID gets an explanation by its ID
+func (db *DB) ExplanationByID(id int) (e *Explanation, err error) {
+	e = &Explanation{}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting explanation %v", id)
+
+	err = pgxscan.Get(ctx, db.Pool, e, "select id, name, aliases, description, created, as_command from public.explanations where id = $1", id)
+	return e, err
+}
+
+// UpdateExplanation updates an explanation's name, aliases, and description
+func (db *DB) UpdateExplanation(e *Explanation) (err error) {
+	if e.Aliases == nil {
+		e.Aliases = []string{}
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Updating explanation %v", e.ID)
+
+	commandTag, err := db.Exec(ctx, "update public.explanations set name = $1, aliases = $2, description = $3 where id = $4", e.Name, e.Aliases, e.Description, e.ID)
+	if err != nil {
+		return
+	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return
+}
+
+// RemoveExplanation removes an explanation
+func (db *DB) RemoveExplanation(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Removing explanation %v", id)
+
+	commandTag, err := db.Exec(ctx, "delete from public.explanations where id = $1", id)
+	if err != nil {
+		return
+	}
+	if commandTag.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return
+}
+
+// ExplanationsMentioning returns explanations whose description mentions any of the given names
+func (db *DB) ExplanationsMentioning(names []string) (e []*Explanation, err error) {
+	var patterns []string
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			patterns = append(patterns, "%"+likeEscaper.Replace(n)+"%")
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting explanations mentioning %v", names)
+
+	err = pgxscan.Select(ctx, db.Pool, &e, "select id, name, aliases, description, created, as_command from public.explanations where description ilike any($1) order by id", patterns)
+	return e, err
+}
+
+// likeEscaper escapes wildcards in like patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
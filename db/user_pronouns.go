@@ -0,0 +1,194 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+)
+
+// MaxUserPronouns is the maximum number of sets a user can save to their profile
+const MaxUserPronouns = 10
+
+// ErrTooManyUserPronouns is returned when a user tries to save more than MaxUserPronouns sets
+var ErrTooManyUserPronouns = errors.New("too many saved pronoun sets")
+
+// ErrUserPronounsSaved is returned when a user tries to save a set that's already on their profile
+var ErrUserPronounsSaved = errors.New("pronoun set is already saved")
+
+// UserPronouns is a pronoun set saved to a user's profile
+type UserPronouns struct {
+	ID     int
+	UserID discord.UserID
+
+	// PronounID is nil for custom sets
+	PronounID *int
+	Set       PronounSet
+	Name      *string
+
+	Position int
+	Created  time.Time
+}
+
+// UserPronouns returns all sets saved to the user's profile, in order
+func (db *DB) UserPronouns(userID discord.UserID) (sets []UserPronouns, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Getting pronouns for user %v", userID)
+
+	rows, err := db.Query(ctx, `select
+	u.id, u.user_id, u.pronoun_id, u.name, u.position, u.created,
+	coalesce(p.language, u.language), coalesce(p.forms, u.forms),
+	coalesce(p.subjective, ''), coalesce(p.objective, ''), coalesce(p.poss_det, ''), coalesce(p.poss_pro, ''), coalesce(p.reflexive, ''),
+	coalesce(p.uses, 0)
+	from user_pronouns as u left join pronouns as p on u.pronoun_id = p.id
+	where u.user_id = $1 order by u.position, u.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u UserPronouns
+		err = rows.Scan(
+			&u.ID, &u.UserID, &u.PronounID, &u.Name, &u.Position, &u.Created,
+			&u.Set.Language, &u.Set.Forms,
+			&u.Set.Subjective, &u.Set.Objective, &u.Set.PossDet, &u.Set.PossPro, &u.Set.Reflexive,
+			&u.Set.Uses,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.PronounID != nil {
+			u.Set.ID = *u.PronounID
+		} else if u.Set.Lang() == DefaultLanguage {
+			// custom English sets only have their forms stored
+			u.Set, _ = NewPronounSet(u.Set.Language, u.Set.Forms)
+		}
+
+		sets = append(sets, u)
+	}
+	return sets, rows.Err()
+}
+
+// AddUserPronouns saves a set to the user's profile.
+// If the set has an ID it references that set, otherwise it's saved as a custom set.
+func (db *DB) AddUserPronouns(userID discord.UserID, set PronounSet, name string) (id int, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	var (
+		pronounID *int
+		forms     = []string{}
+		namePtr   *string
+	)
+	if set.ID != 0 {
+		pronounID = &set.ID
+	} else {
+		set, err = NewPronounSet(set.Lang(), set.FormList())
+		if err != nil {
+			return 0, err
+		}
+		forms = set.Forms
+	}
+	if name != "" {
+		namePtr = &name
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// lock the user's sets so concurrent saves can't go over the limit.
+	// the count is a separate statement, so it sees sets saved by a save that held the lock before this one.
+	_, err = tx.Exec(ctx, "select id from user_pronouns where user_id = $1 for update", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		count    int
+		position int
+		exists   bool
+	)
+	err = tx.QueryRow(ctx, `select count(*), coalesce(max(position) + 1, 0),
+	coalesce(bool_or(pronoun_id = $2 or (pronoun_id is null and $2::int is null and language = $3 and forms = $4)), false)
+	from user_pronouns where user_id = $1`, userID, pronounID, set.Lang(), forms).Scan(&count, &position, &exists)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, ErrUserPronounsSaved
+	}
+	if count >= MaxUserPronouns {
+		return 0, ErrTooManyUserPronouns
+	}
+
+	Debug("Saving pronouns %s for user %v", set, userID)
+
+	err = tx.QueryRow(ctx, `insert into user_pronouns (user_id, pronoun_id, language, forms, name, position)
+	values ($1, $2, $3, $4, $5, $6) returning id`, userID, pronounID, set.Lang(), forms, namePtr, position).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// saved sets count towards a set's uses
+	if set.ID != 0 {
+		go db.IncrementPronounUse(&set)
+	}
+	return id, nil
+}
+
+// RemoveUserPronouns removes a set from the user's profile, returning false if it wasn't found
+func (db *DB) RemoveUserPronouns(userID discord.UserID, id int) (removed bool, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	ct, err := db.Exec(ctx, "delete from user_pronouns where user_id = $1 and id = $2", userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() != 0, nil
+}
+
+// ClearUserPronouns removes all sets from the user's profile
+func (db *DB) ClearUserPronouns(userID discord.UserID) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, "delete from user_pronouns where user_id = $1", userID)
+	return
+}
+
+// PronounUserCounts returns the number of user profiles each pronoun set is saved to, keyed by set ID
+func (db *DB) PronounUserCounts() (counts map[int]int64, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	var rows []struct {
+		PronounID int
+		Count     int64
+	}
+
+	err = pgxscan.Select(ctx, db.Pool, &rows, `select pronoun_id, count(distinct user_id) as count
+	from user_pronouns where pronoun_id is not null group by pronoun_id`)
+	if err != nil {
+		return nil, err
+	}
+
+	counts = make(map[int]int64, len(rows))
+	for _, r := range rows {
+		counts[r.PronounID] = r.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// TestAddUserPronounsLimit checks that concurrent saves can't go over MaxUserPronouns, and that a set can't be saved twice
+func TestAddUserPronounsLimit(t *testing.T) {
+	db := testDB(t)
+
+	userID := discord.UserID(time.Now().UnixNano())
+	t.Cleanup(func() { db.ClearUserPronouns(userID) })
+
+	set := func(i int) PronounSet {
+		s, err := NewPronounSet(DefaultLanguage, []string{
+			fmt.Sprint("a", i), fmt.Sprint("b", i), fmt.Sprint("c", i), fmt.Sprint("d", i), fmt.Sprint("e", i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	if _, err := db.AddUserPronouns(userID, set(0), ""); err != nil {
+		t.Fatalf("saving set: %v", err)
+	}
+	if _, err := db.AddUserPronouns(userID, set(0), ""); err != ErrUserPronounsSaved {
+		t.Fatalf("expected ErrUserPronounsSaved, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= MaxUserPronouns*2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := db.AddUserPronouns(userID, set(i), "")
+			if err != nil && err != ErrTooManyUserPronouns {
+				t.Errorf("saving set %v: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	saved, err := db.UserPronouns(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != MaxUserPronouns {
+		t.Fatalf("expected %v saved sets, got %v", MaxUserPronouns, len(saved))
+	}
+
+	positions := map[int]bool{}
+	for _, s := range saved {
+		if positions[s.Position] {
+			t.Fatalf("more than one set has position %v", s.Position)
+		}
+		positions[s.Position] = true
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Maximum lengths of term fields, used by both the bot's admin commands and the API
+const (
+	MaxTermNameLength    = 200
+	MaxDescriptionLength = 1800
+	MaxSourceLength      = 200
+	MaxAliasesLength     = 1000
+	MaxNoteLength        = 1000
+	MaxCWLength          = 1000
+)
+
+// ValidationError is returned when a field is invalid
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+func tooLong(field string, length, max int) error {
+	return &ValidationError{
+		Field:  field,
+		Reason: fmt.Sprintf("too long (%v > %v)", length, max),
+	}
+}
+
+// ValidateTerm checks that all of a term's fields are within limits
+func ValidateTerm(t *Term) error {
+	switch {
+	case strings.TrimSpace(t.Name) == "":
+		return &ValidationError{Field: "name", Reason: "can't be empty"}
+	case len(t.Name) > MaxTermNameLength:
+		return tooLong("name", len(t.Name), MaxTermNameLength)
+	case strings.TrimSpace(t.Description) == "":
+		return &ValidationError{Field: "description", Reason: "can't be empty"}
+	case len(t.Description) > MaxDescriptionLength:
+		return tooLong("description", len(t.Description), MaxDescriptionLength)
+	case len(t.Source) > MaxSourceLength:
+		return tooLong("source", len(t.Source), MaxSourceLength)
+	case len(strings.Join(t.Aliases, ", ")) > MaxAliasesLength:
+		return tooLong("aliases", len(strings.Join(t.Aliases, ", ")), MaxAliasesLength)
+	case len(t.Note) > MaxNoteLength:
+		return tooLong("note", len(t.Note), MaxNoteLength)
+	case len(t.ContentWarnings) > MaxCWLength:
+		return tooLong("content_warnings", len(t.ContentWarnings), MaxCWLength)
+	}
+	return nil
+}
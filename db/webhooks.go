@@ -0,0 +1,234 @@
+package db
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/google/uuid"
+)
+
+// Webhook is an outgoing webhook, which is sent glossary changes.
+type Webhook struct {
+	ID     int      `json:"id"`
+	URL    string   `json:"url"`
+	Secret string   `json:"-"`
+	Events []string `json:"events"`
+
+	CreatedBy discord.UserID `json:"created_by"`
+	Created   time.Time      `json:"created"`
+
+	Failures       int        `json:"failures"`
+	Disabled       *time.Time `json:"disabled,omitempty"`
+	DisabledReason *string    `json:"disabled_reason,omitempty"`
+}
+
+// WebhookDelivery is a single attempt at delivering an event to a webhook.
+// Attempts that haven't been made yet have NextAttempt and Payload set.
+type WebhookDelivery struct {
+	ID         int64
+	WebhookID  int
+	DeliveryID uuid.UUID
+	Event      string
+	Attempt    int
+
+	Status *int
+	Error  *string
+
+	// Timestamp is when the attempt was made, or when it was queued if it's still pending
+	Timestamp   time.Time
+	NextAttempt *time.Time
+	Payload     *string
+}
+
+// CreateWebhook creates a new webhook with a random secret.
+func (db *DB) CreateWebhook(url string, events []string, createdBy discord.UserID) (w Webhook, err error) {
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return w, err
+	}
+
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Creating webhook for %v with events %v", url, events)
+
+	err = pgxscan.Get(ctx, db, &w, "insert into webhooks (url, secret, events, created_by) values ($1, $2, $3, $4) returning *", url, hex.EncodeToString(b), events, createdBy)
+	return w, err
+}
+
+// Webhook returns a webhook by ID.
+func (db *DB) Webhook(id int) (w Webhook, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Get(ctx, db, &w, "select * from webhooks where id = $1", id)
+	return
+}
+
+// Webhooks returns all webhooks, including disabled ones.
+func (db *DB) Webhooks() (w []Webhook, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db, &w, "select * from webhooks order by id")
+	return
+}
+
+// ActiveWebhooks returns all webhooks that aren't disabled.
+func (db *DB) ActiveWebhooks() (w []Webhook, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db, &w, "select * from webhooks where disabled is null order by id")
+	return
+}
+
+// RemoveWebhook deletes a webhook and its delivery log.
+func (db *DB) RemoveWebhook(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Removing webhook %v", id)
+
+	ct, err := db.Exec(ctx, "delete from webhooks where id = $1", id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return nil
+}
+
+// DisableWebhook disables a webhook with the given reason.
+func (db *DB) DisableWebhook(id int, reason string) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Disabling webhook %v: %v", id, reason)
+
+	ct, err := db.Exec(ctx, "update webhooks set disabled = (current_timestamp at time zone 'utc'), disabled_reason = $2 where id = $1", id, reason)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return nil
+}
+
+// EnableWebhook re-enables a webhook and resets its failure count.
+func (db *DB) EnableWebhook(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	Debug("Enabling webhook %v", id)
+
+	ct, err := db.Exec(ctx, "update webhooks set disabled = null, disabled_reason = null, failures = 0 where id = $1", id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != 1 {
+		return ErrorNoRowsAffected
+	}
+	return nil
+}
+
+// QueueWebhookDelivery queues a delivery attempt, to be made at the given time.
+func (db *DB) QueueWebhookDelivery(webhookID int, deliveryID uuid.UUID, event string, attempt int, payload []byte, at time.Time) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, `insert into webhook_deliveries (webhook_id, delivery_id, event, attempt, payload, next_attempt)
+	values ($1, $2, $3, $4, $5, $6)`, webhookID, deliveryID, event, attempt, string(payload), at.UTC())
+	return err
+}
+
+// ClaimWebhookDeliveries returns up to limit pending attempts that are due, and pushes their next attempt back by lease,
+// so other processes don't make them too. If the claiming process stops before finishing an attempt, it's retried after the lease.
+// Pending attempts for disabled webhooks are cancelled.
+func (db *DB) ClaimWebhookDeliveries(limit int, lease time.Duration) (d []WebhookDelivery, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, `update webhook_deliveries set next_attempt = null, payload = null, error = 'webhook was disabled'
+	where next_attempt is not null and webhook_id in (select id from webhooks where disabled is not null)`)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pgxscan.Select(ctx, db, &d, `update webhook_deliveries set next_attempt = (current_timestamp at time zone 'utc') + $2 * interval '1 second'
+	where id in (
+		select id from webhook_deliveries where next_attempt <= (current_timestamp at time zone 'utc')
+		order by next_attempt limit $1 for update skip locked
+	) returning *`, limit, int(lease.Seconds()))
+	return d, err
+}
+
+// FinishWebhookDelivery records the result of an attempt. If retryAt isn't nil, the next attempt is queued for then.
+func (db *DB) FinishWebhookDelivery(d WebhookDelivery, retryAt *time.Time) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `update webhook_deliveries set status = $2, error = $3, next_attempt = null, payload = null,
+	timestamp = (current_timestamp at time zone 'utc') where id = $1`, d.ID, d.Status, d.Error)
+	if err != nil {
+		return err
+	}
+
+	if retryAt != nil {
+		_, err = tx.Exec(ctx, `insert into webhook_deliveries (webhook_id, delivery_id, event, attempt, payload, next_attempt)
+		values ($1, $2, $3, $4, $5, $6)`, d.WebhookID, d.DeliveryID, d.Event, d.Attempt+1, d.Payload, retryAt.UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// WebhookDeliveries returns the latest delivery attempts for a webhook, newest first.
+func (db *DB) WebhookDeliveries(id, limit int) (d []WebhookDelivery, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = pgxscan.Select(ctx, db, &d, "select * from webhook_deliveries where webhook_id = $1 order by id desc limit $2", id, limit)
+	return
+}
+
+// WebhookSucceeded resets a webhook's failure count.
+func (db *DB) WebhookSucceeded(id int) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, "update webhooks set failures = 0 where id = $1 and failures <> 0", id)
+	return err
+}
+
+// WebhookFailed increments a webhook's failure count, returning the new count.
+func (db *DB) WebhookFailed(id int) (failures int, err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	err = db.QueryRow(ctx, "update webhooks set failures = failures + 1 where id = $1 returning failures", id).Scan(&failures)
+	return
+}
+
+// PruneWebhookDeliveries deletes delivery attempts older than the given time. Pending attempts are kept.
+func (db *DB) PruneWebhookDeliveries(before time.Time) (err error) {
+	ctx, cancel := db.Context()
+	defer cancel()
+
+	_, err = db.Exec(ctx, "delete from webhook_deliveries where timestamp < $1 and next_attempt is null", before.UTC())
+	return err
+}
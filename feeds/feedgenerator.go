@@ -1,7 +1,9 @@
 package feeds
 
 import (
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -75,12 +77,20 @@ func (f *Feeds) JSON() (string, error) {
 	return f.cachedJSON, nil
 }
 
+// Invalidate makes the next call to Update regenerate the feeds
+func (f *Feeds) Invalidate() {
+	f.mu.Lock()
+	f.lastUpdated = time.Time{}
+	f.mu.Unlock()
+}
+
 func (f *Feeds) Update() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	if time.Now().Sub(f.lastUpdated) < 24*time.Hour {
 		return nil
 	}
-	f.mu.Lock()
-	defer f.mu.Unlock()
 
 	terms, err := f.db.TermsSince(time.Now().AddDate(0, 0, -feedlength))
 	if err != nil {
@@ -105,6 +115,27 @@ func (f *Feeds) Update() error {
 		})
 	}
 
+	revs, err := f.db.RevisionsSince(time.Now().AddDate(0, 0, -feedlength))
+	if err != nil {
+		return errors.Wrap(err, "fetching term revisions")
+	}
+
+	for _, rev := range revs {
+		items = append(items, &feeds.Item{
+			Title:       "Edited: " + rev.Name,
+			Link:        &feeds.Link{Href: f.schema + f.BaseURL + "/term/" + strconv.Itoa(rev.TermID) + "/history"},
+			Description: revisionSummary(rev),
+			Id:          "revision:" + strconv.FormatInt(rev.ID, 10),
+			Updated:     rev.Timestamp,
+			Created:     rev.Timestamp,
+		})
+	}
+
+	// new terms and edits are mixed, newest first
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Created.After(items[j].Created)
+	})
+
 	f.feed.Items = items
 	atom, err := f.feed.ToAtom()
 	if err != nil {
@@ -124,9 +155,31 @@ func (f *Feeds) Update() error {
 	}
 	f.cachedJSON = json
 
+	f.lastUpdated = time.Now()
 	return nil
 }
 
+// revisionSummary lists the fields changed in a revision.
+// The changes themselves aren't included, as they could be behind a content warning on the term's page.
+func revisionSummary(rev db.Revision) string {
+	fields := make([]string, 0, len(rev.Changes))
+	for _, c := range rev.Changes {
+		fields = append(fields, strings.ToLower(db.FieldName(c.Field)))
+	}
+
+	s := "Changed " + strings.Join(fields, ", ") + "."
+	if len(fields) == 0 {
+		s = "Changed the term's settings."
+	}
+	if rev.Editor != nil {
+		s += " Edited by " + rev.Editor.Name + "."
+	}
+	if rev.Reason != "" {
+		s += " Reason: " + rev.Reason
+	}
+	return s
+}
+
 func New(db *db.DB, urlSchema, baseURL string) *Feeds {
 	feed := &feeds.Feed{
 		Title:       title,
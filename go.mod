@@ -5,7 +5,7 @@ go 1.16
 require (
 	codeberg.org/eviedelta/detctime v0.0.0-20201201223733-52d0e0a1ba3d
 	emperror.dev/errors v0.8.0
-	git.sr.ht/~adnano/go-gemini v0.2.2 // indirect
+	git.sr.ht/~adnano/go-gemini v0.2.2
 	github.com/360EntSecGroup-Skylar/excelize/v2 v2.4.0
 	github.com/BurntSushi/toml v0.4.1
 	github.com/Masterminds/goutils v1.1.1 // indirect
@@ -14,12 +14,14 @@ require (
 	github.com/diamondburned/arikawa/v3 v3.0.0-rc.5.0.20220315203735-eec8fcf719c3
 	github.com/dustin/go-humanize v1.0.0
 	github.com/georgysavva/scany v0.2.8
+	github.com/getkin/kin-openapi v0.61.0
 	github.com/getsentry/sentry-go v0.11.0
 	github.com/go-chi/chi/v5 v5.0.0
 	github.com/go-chi/render v1.0.1
 	github.com/go-sql-driver/mysql v1.6.0 // indirect
 	github.com/google/uuid v1.3.0
-	github.com/gorilla/feeds v1.1.1 // indirect
+	github.com/gorilla/feeds v1.1.1
+	github.com/graph-gophers/graphql-go v1.3.0
 	github.com/imdario/mergo v0.3.12 // indirect
 	github.com/influxdata/influxdb-client-go/v2 v2.5.1
 	github.com/jackc/pgconn v1.8.1
@@ -47,4 +49,5 @@ require (
 	go.uber.org/zap v1.17.0
 	golang.org/x/net v0.0.0-20210525063256-abc453219eb5 // indirect
 	golang.org/x/tools v0.1.1 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/termora/berry/common"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-Termora-Event"
+	DeliveryHeader  = "X-Termora-Delivery"
+	TimestampHeader = "X-Termora-Timestamp"
+	SignatureHeader = "X-Termora-Signature"
+)
+
+// Sign returns the signature header for a payload: "sha256=" followed by the hex-encoded HMAC-SHA256 of "<timestamp>.<body>", keyed with the webhook's secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Worker settings
+const (
+	// pollInterval is how often the worker checks for queued deliveries
+	pollInterval = 5 * time.Second
+	// claimLimit is the maximum number of deliveries claimed at once
+	claimLimit = 20
+	// claimLease is how long a claimed delivery is reserved for, after which another process can retry it.
+	// It has to be longer than the client timeout.
+	claimLease = time.Minute
+)
+
+// worker makes queued delivery attempts as they're due, up to claimLimit at a time.
+// Every process with a Dispatcher runs one, and attempts are claimed in the database, so each attempt is only made by one of them.
+func (dp *Dispatcher) worker() {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+
+	for {
+		deliveries, err := dp.db.ClaimWebhookDeliveries(claimLimit, claimLease)
+		if err != nil {
+			log.Errorf("Error getting queued webhook deliveries: %v", err)
+		}
+		var wg sync.WaitGroup
+		for _, d := range deliveries {
+			wg.Add(1)
+			go func(d db.WebhookDelivery) {
+				defer wg.Done()
+				dp.deliver(d)
+			}(d)
+		}
+		wg.Wait()
+
+		// if there were as many as could be claimed, there might be more waiting
+		if len(deliveries) == claimLimit {
+			continue
+		}
+
+		select {
+		case <-t.C:
+		case <-dp.wake:
+		}
+	}
+}
+
+// deliver makes a single queued delivery attempt, and queues a retry if it fails and there are attempts left.
+func (dp *Dispatcher) deliver(d db.WebhookDelivery) {
+	w, err := dp.db.Webhook(d.WebhookID)
+	if err != nil {
+		// the webhook was deleted, which also deletes its deliveries
+		if errors.Is(err, pgx.ErrNoRows) {
+			return
+		}
+		log.Errorf("Error getting webhook %v: %v", d.WebhookID, err)
+		return
+	}
+	if w.Disabled != nil {
+		s := "webhook was disabled"
+		d.Error = &s
+		if err := dp.db.FinishWebhookDelivery(d, nil); err != nil {
+			log.Errorf("Error logging delivery %v to webhook %v: %v", d.DeliveryID, w.ID, err)
+		}
+		return
+	}
+
+	var body []byte
+	if d.Payload != nil {
+		body = []byte(*d.Payload)
+	}
+
+	status, err := dp.post(w, d.DeliveryID, d.Event, body)
+	if status != 0 {
+		d.Status = &status
+	}
+	if err != nil {
+		s := err.Error()
+		d.Error = &s
+	}
+
+	var retryAt *time.Time
+	if err != nil && d.Attempt <= len(dp.retryDelays) {
+		t := time.Now().Add(dp.retryDelays[d.Attempt-1])
+		retryAt = &t
+	}
+
+	if finishErr := dp.db.FinishWebhookDelivery(d, retryAt); finishErr != nil {
+		log.Errorf("Error logging delivery %v to webhook %v: %v", d.DeliveryID, w.ID, finishErr)
+	}
+
+	if err == nil {
+		if err := dp.db.WebhookSucceeded(w.ID); err != nil {
+			log.Errorf("Error resetting failures for webhook %v: %v", w.ID, err)
+		}
+		return
+	}
+	if retryAt != nil {
+		return
+	}
+
+	failures, err := dp.db.WebhookFailed(w.ID)
+	if err != nil {
+		log.Errorf("Error incrementing failures for webhook %v: %v", w.ID, err)
+		return
+	}
+
+	if failures >= MaxFailures {
+		log.Infof("Disabling webhook %v after %v failed deliveries", w.ID, failures)
+
+		err = dp.db.DisableWebhook(w.ID, fmt.Sprintf("%v deliveries in a row failed", failures))
+		if err != nil {
+			log.Errorf("Error disabling webhook %v: %v", w.ID, err)
+		}
+	}
+}
+
+// post makes a single delivery attempt, returning the response status (if any) and an error if the attempt failed.
+func (dp *Dispatcher) post(w db.Webhook, id uuid.UUID, event string, body []byte) (status int, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	ts := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Termora-Webhooks/"+common.Version)
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, id.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(SignatureHeader, Sign(w.Secret, ts, body))
+
+	resp, err := dp.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// read (some of) the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("non-2xx response: %v", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
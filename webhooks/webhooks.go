@@ -0,0 +1,168 @@
+// Package webhooks sends glossary changes to outgoing webhooks.
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/termora/berry/common/log"
+	"github.com/termora/berry/db"
+)
+
+// Subjects and actions that can be used in event filters
+var (
+	Subjects = []string{"term", "pronouns", "explanation"}
+	Actions  = []string{"create", "update", "delete"}
+)
+
+// MaxFailures is the number of consecutive failed deliveries after which a webhook is disabled
+const MaxFailures = 10
+
+// deliveryRetention is how long the delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// Event is the payload sent to webhooks.
+type Event struct {
+	// Event is "<subject>.<action>", such as "term.update"
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Subject   string `json:"subject"`
+	SubjectID int    `json:"id"`
+	Action    string `json:"action"`
+
+	// AuditLogID is the ID of the audit log entry for this change
+	AuditLogID int64 `json:"audit_log_id"`
+
+	// Before is null for created objects, After is null for deleted objects
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// EventName returns the event name for the given subject and action
+func EventName(subject, action string) string {
+	return subject + "." + action
+}
+
+// ValidFilter returns true if the event filter is valid.
+// Filters are either an event name, "<subject>.*", or "*".
+func ValidFilter(filter string) bool {
+	if filter == "*" {
+		return true
+	}
+
+	parts := strings.SplitN(filter, ".", 2)
+	if len(parts) != 2 || !contains(Subjects, parts[0]) {
+		return false
+	}
+	return parts[1] == "*" || contains(Actions, parts[1])
+}
+
+// Matches returns true if any of the filters match the event name
+func Matches(filters []string, event string) bool {
+	for _, f := range filters {
+		if f == "*" || f == event {
+			return true
+		}
+		if strings.HasSuffix(f, ".*") && strings.HasPrefix(event, strings.TrimSuffix(f, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher sends events to all webhooks subscribed to them.
+type Dispatcher struct {
+	db     *db.DB
+	client *http.Client
+
+	// retryDelays is how long to wait before each retry
+	retryDelays []time.Duration
+
+	// wake starts the worker early when an event is queued
+	wake chan struct{}
+}
+
+// New returns a new Dispatcher, and starts delivering queued events and pruning the delivery log in the background.
+func New(d *db.DB) *Dispatcher {
+	dp := &Dispatcher{
+		db:     d,
+		client: &http.Client{Timeout: 10 * time.Second},
+		retryDelays: []time.Duration{
+			10 * time.Second,
+			time.Minute,
+			5 * time.Minute,
+			30 * time.Minute,
+			2 * time.Hour,
+		},
+		wake: make(chan struct{}, 1),
+	}
+
+	go dp.worker()
+	go dp.pruneLoop()
+	return dp
+}
+
+// Send queues an event for every active webhook subscribed to it.
+// Deliveries happen in the background, so this never blocks.
+func (dp *Dispatcher) Send(ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+
+	go func() {
+		webhooks, err := dp.db.ActiveWebhooks()
+		if err != nil {
+			log.Errorf("Error getting webhooks for %v event: %v", ev.Event, err)
+			return
+		}
+
+		var body []byte
+		for _, w := range webhooks {
+			if !Matches(w.Events, ev.Event) {
+				continue
+			}
+
+			if body == nil {
+				body, err = json.Marshal(ev)
+				if err != nil {
+					log.Errorf("Error marshaling %v event: %v", ev.Event, err)
+					return
+				}
+			}
+
+			err = dp.db.QueueWebhookDelivery(w.ID, uuid.New(), ev.Event, 1, body, time.Now())
+			if err != nil {
+				log.Errorf("Error queueing %v event for webhook %v: %v", ev.Event, w.ID, err)
+			}
+		}
+
+		select {
+		case dp.wake <- struct{}{}:
+		default:
+		}
+	}()
+}
+
+func (dp *Dispatcher) pruneLoop() {
+	for {
+		err := dp.db.PruneWebhookDeliveries(time.Now().Add(-deliveryRetention))
+		if err != nil {
+			log.Errorf("Error pruning webhook deliveries: %v", err)
+		}
+
+		time.Sleep(24 * time.Hour)
+	}
+}